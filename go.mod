@@ -4,11 +4,13 @@ go 1.24.0
 
 require (
 	github.com/golang/geo v0.0.0-20250516193853-92f93c4cb289
+	github.com/klauspost/compress v1.18.0
 	github.com/markus-wa/demoinfocs-golang/v5 v5.2.0
 	github.com/markus-wa/gobitread v0.2.5-0.20241202000432-3c3e0bc797c6
 	github.com/oklog/ulid/v2 v2.1.1
 	github.com/pkg/errors v0.9.1
 	google.golang.org/protobuf v1.36.11
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
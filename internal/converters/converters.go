@@ -40,6 +40,34 @@ func BoolToString(value bool) string {
 	return "0"
 }
 
+// Converts values of unknown types with the same format as the typed converters.
+func AnyToString(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case bool:
+		return BoolToString(value)
+	case int:
+		return IntToString(value)
+	case int64:
+		return Int64ToString(value)
+	case uint32:
+		return Uint32ToString(value)
+	case uint64:
+		return Uint64ToString(value)
+	case float32:
+		return Float32ToString(value)
+	case float64:
+		return Float64ToString(value)
+	case fmt.Stringer:
+		return value.String()
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
 func ByteToString(value byte) string {
 	return fmt.Sprintf("%d", value)
 }
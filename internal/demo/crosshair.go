@@ -0,0 +1,114 @@
+package demo
+
+import (
+	"errors"
+	"math"
+)
+
+const crosshairShareCodeByteCount = 18
+
+var ErrInvalidCrosshairShareCode = errors.New("invalid crosshair share code")
+
+type CrosshairSettings struct {
+	Style                      int     `json:"style"` // cl_crosshairstyle
+	Size                       float64 `json:"size"`
+	Gap                        float64 `json:"gap"`
+	Thickness                  float64 `json:"thickness"`
+	Color                      int     `json:"color"` // cl_crosshaircolor, 5 means custom RGB values
+	Red                        uint8   `json:"red"`
+	Green                      uint8   `json:"green"`
+	Blue                       uint8   `json:"blue"`
+	Alpha                      uint8   `json:"alpha"`
+	IsAlphaEnabled             bool    `json:"isAlphaEnabled"`
+	IsOutlineEnabled           bool    `json:"isOutlineEnabled"`
+	OutlineThickness           float64 `json:"outlineThickness"`
+	IsCenterDotEnabled         bool    `json:"isCenterDotEnabled"`
+	IsTStyleEnabled            bool    `json:"isTStyleEnabled"`
+	IsFollowRecoilEnabled      bool    `json:"isFollowRecoilEnabled"`
+	IsDeployedWeaponGapEnabled bool    `json:"isDeployedWeaponGapEnabled"`
+	FixedGap                   float64 `json:"fixedGap"`
+	SplitDistance              int     `json:"splitDistance"`
+	InnerSplitAlpha            float64 `json:"innerSplitAlpha"`
+	OuterSplitAlpha            float64 `json:"outerSplitAlpha"`
+	SplitSizeRatio             float64 `json:"splitSizeRatio"`
+}
+
+func crosshairChecksum(bytes []byte) byte {
+	var sum int
+	for _, b := range bytes[1:] {
+		sum += int(b)
+	}
+
+	return byte(sum % 256)
+}
+
+func toTenths(value float64) int {
+	return int(math.Round(value * 10))
+}
+
+func boolToBit(value bool, position int) byte {
+	if value {
+		return 1 << position
+	}
+
+	return 0
+}
+
+func DecodeCrosshairShareCode(shareCode string) (CrosshairSettings, error) {
+	bytes, err := shareCodeToBytes(shareCode, crosshairShareCodeByteCount)
+	if err != nil || bytes[0] != crosshairChecksum(bytes) {
+		return CrosshairSettings{}, ErrInvalidCrosshairShareCode
+	}
+
+	return CrosshairSettings{
+		Gap:                        float64(int8(bytes[2])) / 10,
+		OutlineThickness:           float64(bytes[3]) / 2,
+		Red:                        bytes[4],
+		Green:                      bytes[5],
+		Blue:                       bytes[6],
+		Alpha:                      bytes[7],
+		SplitDistance:              int(bytes[8] & 0x7),
+		IsFollowRecoilEnabled:      bytes[8]&0x80 != 0,
+		FixedGap:                   float64(int8(bytes[9])) / 10,
+		Color:                      int(bytes[10] & 0x7),
+		IsOutlineEnabled:           bytes[10]&0x8 != 0,
+		InnerSplitAlpha:            float64(bytes[10]>>4) / 10,
+		OuterSplitAlpha:            float64(bytes[11]&0xf) / 10,
+		SplitSizeRatio:             float64(bytes[11]>>4) / 10,
+		Thickness:                  float64(bytes[12]) / 10,
+		Style:                      int(bytes[13]&0xf) >> 1,
+		IsCenterDotEnabled:         bytes[13]&0x10 != 0,
+		IsDeployedWeaponGapEnabled: bytes[13]&0x20 != 0,
+		IsAlphaEnabled:             bytes[13]&0x40 != 0,
+		IsTStyleEnabled:            bytes[13]&0x80 != 0,
+		Size:                       float64(int(bytes[15]&0x1f)<<8|int(bytes[14])) / 10,
+	}, nil
+}
+
+func EncodeCrosshairShareCode(settings CrosshairSettings) string {
+	size := toTenths(settings.Size)
+	bytes := []byte{
+		0,
+		1,
+		byte(int8(toTenths(settings.Gap))),
+		byte(math.Round(settings.OutlineThickness * 2)),
+		settings.Red,
+		settings.Green,
+		settings.Blue,
+		settings.Alpha,
+		byte(settings.SplitDistance&0x7) | boolToBit(settings.IsFollowRecoilEnabled, 7),
+		byte(int8(toTenths(settings.FixedGap))),
+		byte(settings.Color&0x7) | boolToBit(settings.IsOutlineEnabled, 3) | byte(toTenths(settings.InnerSplitAlpha)&0xf)<<4,
+		byte(toTenths(settings.OuterSplitAlpha)&0xf) | byte(toTenths(settings.SplitSizeRatio)&0xf)<<4,
+		byte(toTenths(settings.Thickness)),
+		byte(settings.Style&0x7)<<1 | boolToBit(settings.IsCenterDotEnabled, 4) | boolToBit(settings.IsDeployedWeaponGapEnabled, 5) |
+			boolToBit(settings.IsAlphaEnabled, 6) | boolToBit(settings.IsTStyleEnabled, 7),
+		byte(size & 0xff),
+		byte(size >> 8 & 0x1f),
+		0,
+		0,
+	}
+	bytes[0] = crosshairChecksum(bytes)
+
+	return bytesToShareCode(bytes)
+}
@@ -0,0 +1,72 @@
+package demo
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCrosshairShareCodeRoundTrip(t *testing.T) {
+	samples := []CrosshairSettings{
+		{},
+		{
+			Style:                 4,
+			Size:                  2.5,
+			Gap:                   -3,
+			Thickness:             1,
+			Color:                 5,
+			Red:                   0,
+			Green:                 255,
+			Blue:                  170,
+			Alpha:                 200,
+			IsAlphaEnabled:        true,
+			IsOutlineEnabled:      true,
+			OutlineThickness:      1.5,
+			IsCenterDotEnabled:    true,
+			IsFollowRecoilEnabled: true,
+			FixedGap:              3,
+			SplitDistance:         7,
+			InnerSplitAlpha:       0.5,
+			OuterSplitAlpha:       1,
+			SplitSizeRatio:        0.3,
+		},
+		{
+			Style:                      2,
+			Size:                       500,
+			Gap:                        12.7,
+			Thickness:                  6,
+			Color:                      1,
+			IsTStyleEnabled:            true,
+			IsDeployedWeaponGapEnabled: true,
+			FixedGap:                   -12.8,
+		},
+	}
+
+	for _, settings := range samples {
+		shareCode := EncodeCrosshairShareCode(settings)
+		if !strings.HasPrefix(shareCode, "CSGO-") || len(shareCode) != 34 {
+			t.Errorf("unexpected share code format %s", shareCode)
+		}
+
+		decoded, err := DecodeCrosshairShareCode(shareCode)
+		if err != nil {
+			t.Errorf("failed to decode %s: %v", shareCode, err)
+			continue
+		}
+		if decoded != settings {
+			t.Errorf("expected %+v got %+v", settings, decoded)
+		}
+	}
+}
+
+func TestDecodeCrosshairShareCodeInvalid(t *testing.T) {
+	shareCode := EncodeCrosshairShareCode(CrosshairSettings{Style: 4, Size: 2, Thickness: 1})
+	// Changing the first character changes the least significant byte and so breaks the checksum.
+	tampered := "CSGO-" + string(dictionary[(strings.IndexByte(dictionary, shareCode[5])+1)%len(dictionary)]) + shareCode[6:]
+
+	for _, code := range []string{"", "CSGO-abc", "CSGO-IIIII-IIIII-IIIII-IIIII-IIIII", tampered} {
+		if _, err := DecodeCrosshairShareCode(code); !errors.Is(err, ErrInvalidCrosshairShareCode) {
+			t.Errorf("expected share code %q to be invalid got %v", code, err)
+		}
+	}
+}
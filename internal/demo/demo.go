@@ -1,10 +1,10 @@
 package demo
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"hash/crc64"
-	"io"
 	"os"
 	"regexp"
 	"strconv"
@@ -33,12 +33,14 @@ type Demo struct {
 	MapName                       string
 	NetMessageDecryptionPublicKey []byte
 	NetworkProtocol               int
-	BuildNumber                   int           // Source 2 demos only
-	TickCount                     int           // Not available for Source 2 demos, it's updated during parsing
-	TickRate                      float64       // Not available for Source 2 demos, it's updated during parsing
-	FrameRate                     float64       // Not available for Source 2 demos, it's updated during parsing
-	Duration                      time.Duration // Not available for Source 2 demos, it's updated during parsing
-	ShareCode                     string        // Valve demos only, the .info file must be next to the .dem file to be able to generate it
+	BuildNumber                   int                               // Source 2 demos only
+	TickCount                     int                               // Not available for Source 2 demos, it's updated during parsing
+	TickRate                      float64                           // Not available for Source 2 demos, it's updated during parsing
+	FrameRate                     float64                           // Not available for Source 2 demos, it's updated during parsing
+	Duration                      time.Duration                     // Not available for Source 2 demos, it's updated during parsing
+	ShareCode                     string                            // Valve demos only, the .info file must be next to the .dem file to be able to generate it
+	MatchInfo                     *msg.CDataGCCStrike15V2_MatchInfo // Content of the .info file if it exists
+	checksumData                  string
 }
 
 var faceItDemoNameRegex = regexp.MustCompile(`/[0-9]+_team[a-z0-9-]+-Team[a-z0-9-]+_de_[a-z0-9]+\.dem/`)
@@ -50,25 +52,35 @@ var fiveEPlayDemoNameRegex = regexp.MustCompile(`^g\d+-(.*)[a-zA-Z0-9_]*$`)
 var matchZyDemoNameRegex = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2}_\d{2}-\d{2}-\d{2})_(\d+)_([a-zA-Z0-9_]+)_(.+?)_vs_(.+)$`)
 
 // Reads the .info file associated with a demo if it exists and returns its content as bytes.
-func getMatchInfoProtoBytes(demoFilePath string) []byte {
+func getMatchInfoProtoBytes(demoFilePath string) ([]byte, error) {
 	infoFilePath := demoFilePath + ".info"
 	if _, err := os.Stat(infoFilePath); err != nil {
-		return nil
+		return nil, nil
 	}
 
-	file, err := os.Open(infoFilePath)
+	bytes, err := os.ReadFile(infoFilePath)
 	if err != nil {
-		fmt.Printf("Unable to open .info file: %v", err)
-		return nil
+		return nil, fmt.Errorf("unable to read .info file: %w", err)
 	}
 
-	bytes, err := io.ReadAll(file)
+	return bytes, nil
+}
+
+// Reads and unmarshals the .info file associated with a demo, returns nil without error if it doesn't exist.
+// For compressed demos such as "demo.dem.gz", the file "demo.dem.info" is used.
+func GetMatchInfo(demoFilePath string) (*msg.CDataGCCStrike15V2_MatchInfo, error) {
+	matchInfoBytes, err := getMatchInfoProtoBytes(TrimCompressedFileExtension(demoFilePath))
+	if err != nil || len(matchInfoBytes) == 0 {
+		return nil, err
+	}
+
+	matchInfo := new(msg.CDataGCCStrike15V2_MatchInfo)
+	err = proto.Unmarshal(matchInfoBytes, matchInfo)
 	if err != nil {
-		fmt.Printf("Unable to read .info file: %v", err)
-		return nil
+		return nil, fmt.Errorf("failed to unmarshal MatchInfo message: %w", err)
 	}
 
-	return bytes
+	return matchInfo, nil
 }
 
 func getNetMessageDecryptionKeyFromPubKey(clDecryptDataKeyPub uint64) []byte {
@@ -79,6 +91,9 @@ func getDateFromMatchTime(matchTime uint32) time.Time {
 	return time.Unix(int64(matchTime), 0)
 }
 
+// Reads the demo header of the given file, only the beginning of compressed files is decompressed.
+// The checksum depends on the size of the .dem file, it's empty for compressed files because getting it requires to
+// decompress the whole archive, use AnalyzeDemo to get it.
 func GetDemoFromPath(demoPath string) (*Demo, error) {
 	file, err := os.Open(demoPath)
 	if err != nil {
@@ -94,11 +109,44 @@ func GetDemoFromPath(demoPath string) (*Demo, error) {
 		return nil, err
 	}
 
-	br := bitread.NewLargeBitReader(file)
+	reader, err := NewReader(file)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	// An invalid .info file is ignored, the header is still readable.
+	matchInfo, _ := GetMatchInfo(demoPath)
+	demo, err := GetDemoFromReader(reader, demoPath, matchInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	demo.FilePath = filepath.GetAbsoluteFilePath(demoPath)
+	if demo.Date.IsZero() {
+		demo.Date = stats.ModTime()
+	}
+	if !reader.IsCompressed {
+		demo.SetFileSize(stats.Size())
+	}
+
+	return demo, nil
+}
+
+// Reads the demo header without consuming the reader.
+// Because the size of the demo is part of the checksum, it's available only once SetFileSize has been called.
+// The date is zero if matchInfo is nil.
+func GetDemoFromReader(reader *Reader, name string, matchInfo *msg.CDataGCCStrike15V2_MatchInfo) (*Demo, error) {
+	headerBytes, err := reader.peekHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	br := bitread.NewLargeBitReader(bytes.NewReader(headerBytes))
 	filestamp := br.ReadCString(8)
 	isSource2 := filestamp == "PBDEMS2"
 
-	var checksum string
+	var checksumData string
 	var mapName string
 	var serverName string
 	var clientName string
@@ -109,11 +157,11 @@ func GetDemoFromPath(demoPath string) (*Demo, error) {
 	var tickRate float64
 	var networkProtocol int
 	var buildNumber int
-	var date = stats.ModTime()
+	var date time.Time
 	var shareCode string
 	var netMessageDecryptionPublicKey []byte
 	demoType := constants.DemoTypeGOTV
-	matchInfoBytes := getMatchInfoProtoBytes(demoPath)
+	m := matchInfo
 
 	if isSource2 {
 		br.ReadBytes(8)
@@ -135,8 +183,8 @@ func GetDemoFromPath(demoPath string) (*Demo, error) {
 		mapName = header.GetMapName()
 		serverName = header.GetServerName()
 		clientName = header.GetClientName()
-		data := fmt.Sprintf(
-			"%s%s%s%d%d%s%s%d",
+		checksumData = fmt.Sprintf(
+			"%s%s%s%d%d%s%s",
 			mapName,
 			str.RemoveInvalidUTF8Sequences(serverName),
 			str.RemoveInvalidUTF8Sequences(clientName),
@@ -144,9 +192,7 @@ func GetDemoFromPath(demoPath string) (*Demo, error) {
 			header.GetBuildNum(),
 			header.GetDemoVersionGuid(),
 			header.GetDemoVersionName(),
-			stats.Size(),
 		)
-		checksum = strconv.FormatUint(crc64.Checksum([]byte(data), crc64.MakeTable(crc64.ECMA)), 16)
 
 		serverName = str.ReplaceUTF8ByteSequences(serverName)
 		clientName = str.ReplaceUTF8ByteSequences(clientName)
@@ -157,23 +203,17 @@ func GetDemoFromPath(demoPath string) (*Demo, error) {
 			demoType = constants.DemoTypePOV
 		}
 
-		if len(matchInfoBytes) > 0 {
-			m := new(msg.CDataGCCStrike15V2_MatchInfo)
-			err = proto.Unmarshal(matchInfoBytes, m)
-			if err != nil {
-				fmt.Printf("failed to unmarshal MatchInfo message: %v", err)
-			} else {
-				netMessageDecryptionPublicKey = getNetMessageDecryptionKeyFromPubKey(m.Watchablematchinfo.GetClDecryptdataKeyPub())
-				date = getDateFromMatchTime(m.GetMatchtime())
-				rounds := m.GetRoundstatsall()
-				if len(rounds) > 0 {
-					lastRound := rounds[len(rounds)-1]
-					shareCode = encodeMatchShareCode(MatchInformation{
-						MatchId:       m.GetMatchid(),
-						ReservationId: lastRound.GetReservationid(),
-						TvPort:        m.GetWatchablematchinfo().GetTvPort(),
-					})
-				}
+		if m != nil {
+			netMessageDecryptionPublicKey = getNetMessageDecryptionKeyFromPubKey(m.Watchablematchinfo.GetClDecryptdataKeyPub())
+			date = getDateFromMatchTime(m.GetMatchtime())
+			rounds := m.GetRoundstatsall()
+			if len(rounds) > 0 {
+				lastRound := rounds[len(rounds)-1]
+				shareCode = encodeMatchShareCode(MatchInformation{
+					MatchId:       m.GetMatchid(),
+					ReservationId: lastRound.GetReservationid(),
+					TvPort:        m.GetWatchablematchinfo().GetTvPort(),
+				})
 			}
 		}
 	} else {
@@ -193,8 +233,8 @@ func GetDemoFromPath(demoPath string) (*Demo, error) {
 			tickRate = float64(tickCount) / duration.Seconds()
 		}
 
-		data := fmt.Sprintf(
-			"%s%s%s%d%d%d%d%d",
+		checksumData = fmt.Sprintf(
+			"%s%s%s%d%d%d%d",
 			mapName,
 			serverName,
 			clientName,
@@ -202,40 +242,32 @@ func GetDemoFromPath(demoPath string) (*Demo, error) {
 			tickCount,
 			networkProtocol,
 			signonLength,
-			stats.Size(),
 		)
-		checksum = strconv.FormatUint(crc64.Checksum([]byte(data), crc64.MakeTable(crc64.ECMA)), 16)
-
-		if len(matchInfoBytes) > 0 {
-			m := new(msg.CDataGCCStrike15V2_MatchInfo)
-			err = proto.Unmarshal(matchInfoBytes, m)
-			if err != nil {
-				fmt.Printf("failed to unmarshal MatchInfo message: %v", err)
-			} else {
-				netMessageDecryptionPublicKey = getNetMessageDecryptionKeyFromPubKey(m.Watchablematchinfo.GetClDecryptdataKeyPub())
-				date = getDateFromMatchTime(m.GetMatchtime())
-				lastRound := m.GetRoundstatsLegacy()
-				rounds := m.GetRoundstatsall()
-				if lastRound == nil && len(rounds) > 0 {
-					lastRound = rounds[len(rounds)-1]
-				}
-				if lastRound != nil {
-					shareCode = encodeMatchShareCode(MatchInformation{
-						MatchId:       m.GetMatchid(),
-						ReservationId: lastRound.GetReservationid(),
-						TvPort:        m.GetWatchablematchinfo().GetTvPort(),
-					})
-				}
+
+		if m != nil {
+			netMessageDecryptionPublicKey = getNetMessageDecryptionKeyFromPubKey(m.Watchablematchinfo.GetClDecryptdataKeyPub())
+			date = getDateFromMatchTime(m.GetMatchtime())
+			lastRound := m.GetRoundstatsLegacy()
+			rounds := m.GetRoundstatsall()
+			if lastRound == nil && len(rounds) > 0 {
+				lastRound = rounds[len(rounds)-1]
+			}
+			if lastRound != nil {
+				shareCode = encodeMatchShareCode(MatchInformation{
+					MatchId:       m.GetMatchid(),
+					ReservationId: lastRound.GetReservationid(),
+					TvPort:        m.GetWatchablematchinfo().GetTvPort(),
+				})
 			}
 		}
 	}
 
 	return &Demo{
-		FilePath:                      filepath.GetAbsoluteFilePath(demoPath),
-		FileName:                      filepath.GetFileNameWithoutExtension(demoPath),
+		FilePath:                      name,
+		FileName:                      filepath.GetFileNameWithoutExtension(TrimCompressedFileExtension(name)),
 		Type:                          demoType,
 		Filestamp:                     filestamp,
-		Checksum:                      checksum,
+		checksumData:                  checksumData,
 		Date:                          date,
 		ServerName:                    serverName,
 		ClientName:                    clientName,
@@ -248,82 +280,103 @@ func GetDemoFromPath(demoPath string) (*Demo, error) {
 		BuildNumber:                   buildNumber,
 		NetMessageDecryptionPublicKey: netMessageDecryptionPublicKey,
 		ShareCode:                     shareCode,
+		MatchInfo:                     m,
 	}, nil
 }
 
+// The checksum is computed from the header and the size of the .dem file.
+func (demo *Demo) SetFileSize(size int64) {
+	data := fmt.Sprintf("%s%d", demo.checksumData, size)
+	demo.Checksum = strconv.FormatUint(crc64.Checksum([]byte(data), crc64.MakeTable(crc64.ECMA)), 16)
+}
+
 func (demo *Demo) IsSource2() bool {
 	return demo.Filestamp == "PBDEMS2"
 }
 
-func GetDemoSource(demo *Demo) constants.DemoSource {
-	demoName := strings.ToLower(demo.FileName)
-	serverName := strings.ToLower(demo.ServerName)
-
-	if strings.Contains(serverName, "faceit") || strings.Contains(serverName, "blast") || faceItDemoNameRegex.MatchString(demoName) {
-		return constants.DemoSourceFaceIt
-	}
-
-	if strings.Contains(serverName, "cevo") {
-		return constants.DemoSourceCEVO
-	}
-
-	if strings.Contains(serverName, "challengermode") || strings.Contains(serverName, "pgl major cs2") {
-		return constants.DemoSourceChallengermode
-	}
-
-	if strings.Contains(serverName, "esl") {
-		return constants.DemoSourceESL
-	}
-
-	if strings.Contains(serverName, "ebot") || ebotDemoNameRegex.MatchString(demoName) {
-		return constants.DemoSourceEbot
-	}
-
-	if strings.Contains(serverName, "esea") || strings.Contains(demoName, "esea") {
-		return constants.DemoSourceESEA
-	}
-
-	if strings.Contains(serverName, "popflash") || strings.Contains(demoName, "popflash") {
-		return constants.DemoSourcePopFlash
-	}
-
-	if strings.Contains(serverName, "esportal") {
-		return constants.DemoSourceEsportal
-	}
+type sourceDetectionRule struct {
+	source      constants.DemoSource
+	description string
+	matches     func(demoName string, serverName string) bool
+}
 
-	if strings.Contains(serverName, "fastcup") {
-		return constants.DemoSourceFastcup
+func serverNameContains(source constants.DemoSource, value string) sourceDetectionRule {
+	return sourceDetectionRule{
+		source:      source,
+		description: fmt.Sprintf("server name contains %q", value),
+		matches: func(demoName string, serverName string) bool {
+			return strings.Contains(serverName, value)
+		},
 	}
+}
 
-	if strings.Contains(serverName, "gamersclub") {
-		return constants.DemoSourceGamersclub
+func demoNameContains(source constants.DemoSource, value string) sourceDetectionRule {
+	return sourceDetectionRule{
+		source:      source,
+		description: fmt.Sprintf("demo name contains %q", value),
+		matches: func(demoName string, serverName string) bool {
+			return strings.Contains(demoName, value)
+		},
 	}
+}
 
-	if strings.Contains(serverName, "renown") || strings.Contains(demoName, "renown") {
-		return constants.DemoSourceRenown
+func demoNameMatches(source constants.DemoSource, regex *regexp.Regexp) sourceDetectionRule {
+	return sourceDetectionRule{
+		source:      source,
+		description: fmt.Sprintf("demo name matches %s", regex.String()),
+		matches: func(demoName string, serverName string) bool {
+			return regex.MatchString(demoName)
+		},
 	}
+}
 
-	if strings.Contains(serverName, "matchzy") || matchZyDemoNameRegex.MatchString(demoName) {
-		return constants.DemoSourceMatchZy
-	}
+// Rules are checked in order, the first one matching gives the demo source.
+var sourceDetectionRules = []sourceDetectionRule{
+	serverNameContains(constants.DemoSourceFaceIt, "faceit"),
+	serverNameContains(constants.DemoSourceFaceIt, "blast"),
+	demoNameMatches(constants.DemoSourceFaceIt, faceItDemoNameRegex),
+	serverNameContains(constants.DemoSourceCEVO, "cevo"),
+	serverNameContains(constants.DemoSourceChallengermode, "challengermode"),
+	serverNameContains(constants.DemoSourceChallengermode, "pgl major cs2"),
+	serverNameContains(constants.DemoSourceESL, "esl"),
+	serverNameContains(constants.DemoSourceEbot, "ebot"),
+	demoNameMatches(constants.DemoSourceEbot, ebotDemoNameRegex),
+	serverNameContains(constants.DemoSourceESEA, "esea"),
+	demoNameContains(constants.DemoSourceESEA, "esea"),
+	serverNameContains(constants.DemoSourcePopFlash, "popflash"),
+	demoNameContains(constants.DemoSourcePopFlash, "popflash"),
+	serverNameContains(constants.DemoSourceEsportal, "esportal"),
+	serverNameContains(constants.DemoSourceFastcup, "fastcup"),
+	serverNameContains(constants.DemoSourceGamersclub, "gamersclub"),
+	serverNameContains(constants.DemoSourceRenown, "renown"),
+	demoNameContains(constants.DemoSourceRenown, "renown"),
+	serverNameContains(constants.DemoSourceMatchZy, "matchzy"),
+	demoNameMatches(constants.DemoSourceMatchZy, matchZyDemoNameRegex),
+	serverNameContains(constants.DemoSourceValve, "valve"),
+	serverNameContains(constants.DemoSourcePerfectWorld, "完美世界"),
+	serverNameContains(constants.DemoSourceEsplay, "esplay"),
+	demoNameMatches(constants.DemoSourceFiveEPlay, fiveEPlayDemoNameRegex),
+}
 
-	if strings.Contains(serverName, "valve") {
-		return constants.DemoSourceValve
-	}
+// Returns the detected source and the description of the rule that matched, the description is empty if no rule
+// matched.
+func DetectDemoSource(demo *Demo) (constants.DemoSource, string) {
+	demoName := strings.ToLower(demo.FileName)
+	serverName := strings.ToLower(demo.ServerName)
 
-	if strings.Contains(serverName, "完美世界") {
-		return constants.DemoSourcePerfectWorld
+	for _, rule := range sourceDetectionRules {
+		if rule.matches(demoName, serverName) {
+			return rule.source, rule.description
+		}
 	}
 
-	if strings.Contains(serverName, "esplay") {
-		return constants.DemoSourceEsplay
-	}
+	return constants.DemoSourceUnknown, ""
+}
 
-	if fiveEPlayDemoNameRegex.MatchString(demoName) {
-		return constants.DemoSourceFiveEPlay
-	}
+func GetDemoSource(demo *Demo) constants.DemoSource {
+	source, _ := DetectDemoSource(demo)
 
-	return constants.DemoSourceUnknown
+	return source
 }
 
 func getMapNameFromHeaderMapName(headerMapName string) string {
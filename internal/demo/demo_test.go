@@ -0,0 +1,48 @@
+package demo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/akiver/cs-demo-analyzer/pkg/api/constants"
+)
+
+func TestDetectDemoSource(t *testing.T) {
+	samples := []struct {
+		demo   Demo
+		source constants.DemoSource
+		rule   string
+	}{
+		{Demo{FileName: "match", ServerName: "FACEIT.com register to play here"}, constants.DemoSourceFaceIt, `server name contains "faceit"`},
+		{Demo{FileName: "esea_match_123", ServerName: "server"}, constants.DemoSourceESEA, `demo name contains "esea"`},
+		{Demo{FileName: "2024-01-01_20-00-00_1_de_dust2_team1_vs_team2", ServerName: "server"}, constants.DemoSourceMatchZy, "demo name matches " + matchZyDemoNameRegex.String()},
+		{Demo{FileName: "match", ServerName: "Valve Counter-Strike 2 eu_west Server"}, constants.DemoSourceValve, `server name contains "valve"`},
+		{Demo{FileName: "match", ServerName: "server"}, constants.DemoSourceUnknown, ""},
+	}
+
+	for _, sample := range samples {
+		source, rule := DetectDemoSource(&sample.demo)
+		if source != sample.source || rule != sample.rule {
+			t.Errorf("Expected source %s with rule %q for %+v, got %s with rule %q", sample.source, sample.rule, sample.demo, source, rule)
+		}
+	}
+}
+
+func TestGetMatchInfo(t *testing.T) {
+	folder := t.TempDir()
+	demoPath := filepath.Join(folder, "match.dem.gz")
+
+	matchInfo, err := GetMatchInfo(demoPath)
+	if matchInfo != nil || err != nil {
+		t.Fatalf("Expected no match info and no error without .info file, got %v %v", matchInfo, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(folder, "match.dem.info"), []byte{0xff, 0xff, 0xff}, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	matchInfo, err = GetMatchInfo(demoPath)
+	if matchInfo != nil || err == nil {
+		t.Fatalf("Expected an error with an invalid .info file, got %v %v", matchInfo, err)
+	}
+}
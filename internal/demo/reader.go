@@ -0,0 +1,209 @@
+package demo
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Big enough to contain the demo header, including the CS2 CDemoFileHeader message.
+const headerBufferSize = 64 * 1024
+
+var (
+	gzipMagic  = []byte{0x1f, 0x8b}
+	bzip2Magic = []byte("BZh")
+	zstdMagic  = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	zipMagic   = []byte("PK\x03\x04")
+)
+
+var compressedFileExtensions = []string{".gz", ".bz2", ".zst", ".zip"}
+
+// Reader reads a demo from a stream that may be compressed with gzip, bzip2, zstd or zip (single entry).
+// The demo header is peeked so that the stream is consumed only once, by the parser.
+type Reader struct {
+	IsCompressed bool
+	buffered     *bufio.Reader
+	closers      []io.Closer
+	readCount    int64
+}
+
+func (reader *Reader) Read(p []byte) (int, error) {
+	n, err := reader.buffered.Read(p)
+	reader.readCount += int64(n)
+
+	return n, err
+}
+
+func (reader *Reader) peekHeader() ([]byte, error) {
+	header, err := reader.buffered.Peek(headerBufferSize)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, err
+	}
+
+	return header, nil
+}
+
+// Reads the rest of the stream and returns the size of the decompressed demo.
+func (reader *Reader) Size() (int64, error) {
+	_, err := io.Copy(io.Discard, reader)
+
+	return reader.readCount, err
+}
+
+func (reader *Reader) Close() error {
+	var errs []error
+	for i := len(reader.closers) - 1; i >= 0; i-- {
+		errs = append(errs, reader.closers[i].Close())
+	}
+
+	return errors.Join(errs...)
+}
+
+type closerFunc func() error
+
+func (fn closerFunc) Close() error {
+	return fn()
+}
+
+// zip.NewReader requires an io.ReaderAt, streams are written into a temporary file.
+func openZipEntry(reader io.Reader, file *os.File, closers *[]io.Closer) (io.Reader, error) {
+	if file == nil {
+		tmpFile, err := os.CreateTemp("", "csda-*.zip")
+		if err != nil {
+			return nil, err
+		}
+		*closers = append(*closers, closerFunc(func() error {
+			tmpFile.Close()
+			return os.Remove(tmpFile.Name())
+		}))
+		if _, err = io.Copy(tmpFile, reader); err != nil {
+			return nil, err
+		}
+		file = tmpFile
+	}
+
+	stats, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := zip.NewReader(file, stats.Size())
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*zip.File
+	for _, entry := range archive.File {
+		if !entry.FileInfo().IsDir() {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) != 1 {
+		return nil, fmt.Errorf("zip archive must contain exactly 1 file, found %d", len(entries))
+	}
+
+	entryReader, err := entries[0].Open()
+	if err != nil {
+		return nil, err
+	}
+	*closers = append(*closers, entryReader)
+
+	return entryReader, nil
+}
+
+// Files must not have been read yet because the compression is detected from the beginning of the file.
+func NewReader(reader io.Reader) (*Reader, error) {
+	closers := []io.Closer{}
+
+	// The magic number has to be peeked without consuming the stream.
+	// Files are detected from the beginning of the file to be able to use them as io.ReaderAt for zip archives.
+	var magic []byte
+	var decompressed io.Reader
+	file, isFile := reader.(*os.File)
+	if isFile {
+		// Pipes such as stdin are not seekable.
+		stats, err := file.Stat()
+		isFile = err == nil && stats.Mode().IsRegular()
+	}
+	if isFile {
+		magic = make([]byte, len(zipMagic))
+		n, err := file.ReadAt(magic, 0)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		magic = magic[:n]
+		decompressed = file
+	} else {
+		buffered := bufio.NewReader(reader)
+		var err error
+		magic, err = buffered.Peek(len(zipMagic))
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		decompressed = buffered
+	}
+
+	isCompressed := true
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		gzipReader, err := gzip.NewReader(decompressed)
+		if err != nil {
+			return nil, err
+		}
+		closers = append(closers, gzipReader)
+		decompressed = gzipReader
+	case bytes.HasPrefix(magic, bzip2Magic):
+		decompressed = bzip2.NewReader(decompressed)
+	case bytes.HasPrefix(magic, zstdMagic):
+		zstdReader, err := zstd.NewReader(decompressed)
+		if err != nil {
+			return nil, err
+		}
+		closers = append(closers, closerFunc(func() error {
+			zstdReader.Close()
+			return nil
+		}))
+		decompressed = zstdReader
+	case bytes.HasPrefix(magic, zipMagic):
+		var zipFile *os.File
+		if isFile {
+			zipFile = file
+		}
+		entryReader, err := openZipEntry(decompressed, zipFile, &closers)
+		if err != nil {
+			(&Reader{closers: closers}).Close()
+			return nil, err
+		}
+		decompressed = entryReader
+	default:
+		isCompressed = false
+	}
+
+	return &Reader{
+		IsCompressed: isCompressed,
+		buffered:     bufio.NewReaderSize(decompressed, headerBufferSize),
+		closers:      closers,
+	}, nil
+}
+
+// Removes the compression extension if any, "demo.dem.gz" returns "demo.dem".
+func TrimCompressedFileExtension(filePath string) string {
+	extension := strings.ToLower(filepath.Ext(filePath))
+	for _, compressedExtension := range compressedFileExtensions {
+		if extension == compressedExtension {
+			return strings.TrimSuffix(filePath, filepath.Ext(filePath))
+		}
+	}
+
+	return filePath
+}
@@ -0,0 +1,126 @@
+package demo
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func newCSGODemoHeader() []byte {
+	var buffer bytes.Buffer
+	writeString := func(value string, size int) {
+		data := make([]byte, size)
+		copy(data, value)
+		buffer.Write(data)
+	}
+
+	writeString("HL2DEMO", 8)
+	binary.Write(&buffer, binary.LittleEndian, []int32{4, 13881})
+	writeString("Valve CS:GO EU West Server", 260)
+	writeString("GOTV Demo", 260)
+	writeString("de_dust2", 260)
+	writeString("csgo", 260)
+	binary.Write(&buffer, binary.LittleEndian, float32(100))
+	binary.Write(&buffer, binary.LittleEndian, []int32{12800, 6400, 1000})
+	// Fake demo content.
+	buffer.Write(bytes.Repeat([]byte{1, 2, 3, 4}, 50000))
+
+	return buffer.Bytes()
+}
+
+func TestGetDemoFromReaderWithCompressedStreams(t *testing.T) {
+	data := newCSGODemoHeader()
+
+	var gzipBuffer bytes.Buffer
+	gzipWriter := gzip.NewWriter(&gzipBuffer)
+	gzipWriter.Write(data)
+	gzipWriter.Close()
+
+	var zstdBuffer bytes.Buffer
+	zstdWriter, _ := zstd.NewWriter(&zstdBuffer)
+	zstdWriter.Write(data)
+	zstdWriter.Close()
+
+	var zipBuffer bytes.Buffer
+	zipWriter := zip.NewWriter(&zipBuffer)
+	entryWriter, _ := zipWriter.Create("demo.dem")
+	entryWriter.Write(data)
+	zipWriter.Close()
+
+	streams := map[string][]byte{
+		"demo.dem":     data,
+		"demo.dem.gz":  gzipBuffer.Bytes(),
+		"demo.dem.zst": zstdBuffer.Bytes(),
+		"demo.zip":     zipBuffer.Bytes(),
+	}
+
+	for name, stream := range streams {
+		reader, err := NewReader(bytes.NewReader(stream))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		demo, err := GetDemoFromReader(reader, name, nil)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if demo.MapName != "de_dust2" || demo.FileName != "demo" || demo.TickCount != 12800 {
+			t.Errorf("%s: unexpected demo %+v", name, demo)
+		}
+		if reader.IsCompressed != (name != "demo.dem") {
+			t.Errorf("%s: unexpected compression detection", name)
+		}
+
+		// The header must not have been consumed.
+		content, err := io.ReadAll(reader)
+		if err != nil || !bytes.Equal(content, data) {
+			t.Errorf("%s: expected the reader to return the whole demo", name)
+		}
+		size, _ := reader.Size()
+		if size != int64(len(data)) {
+			t.Errorf("%s: expected size %d got %d", name, len(data), size)
+		}
+		reader.Close()
+	}
+}
+
+func TestGetDemoFromPathSkipsChecksumOfCompressedFiles(t *testing.T) {
+	data := newCSGODemoHeader()
+	folder := t.TempDir()
+
+	demoPath := filepath.Join(folder, "demo.dem")
+	if err := os.WriteFile(demoPath, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var gzipBuffer bytes.Buffer
+	gzipWriter := gzip.NewWriter(&gzipBuffer)
+	gzipWriter.Write(data)
+	gzipWriter.Close()
+	archivePath := filepath.Join(folder, "demo.dem.gz")
+	if err := os.WriteFile(archivePath, gzipBuffer.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	demo, err := GetDemoFromPath(demoPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if demo.Checksum == "" {
+		t.Errorf("expected the checksum of the .dem file to be computed")
+	}
+
+	demo, err = GetDemoFromPath(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if demo.MapName != "de_dust2" || demo.Checksum != "" {
+		t.Errorf("expected the header without checksum of the archive got %+v", demo)
+	}
+}
@@ -2,6 +2,7 @@ package demo
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"math/big"
 	"strings"
@@ -11,6 +12,8 @@ const (
 	dictionary = "ABCDEFGHJKLMNOPQRSTUVWXYZabcdefhijkmnopqrstuvwxyz23456789"
 )
 
+var ErrInvalidShareCode = errors.New("invalid share code")
+
 type MatchInformation struct {
 	MatchId       uint64
 	ReservationId uint64
@@ -34,6 +37,31 @@ func bytesToShareCode(bytes []byte) string {
 	return fmt.Sprintf("CSGO-%s-%s-%s-%s-%s", str[0:5], str[5:10], str[10:15], str[15:20], str[20:25])
 }
 
+// The share code is the base 57 representation of the bytes, least significant character first.
+func shareCodeToBytes(shareCode string, byteCount int) ([]byte, error) {
+	code := strings.ReplaceAll(strings.TrimPrefix(shareCode, "CSGO-"), "-", "")
+	if len(code) != 25 {
+		return nil, ErrInvalidShareCode
+	}
+
+	dictionaryLength := big.NewInt(int64(len(dictionary)))
+	total := new(big.Int)
+	for i := len(code) - 1; i >= 0; i-- {
+		index := strings.IndexByte(dictionary, code[i])
+		if index == -1 {
+			return nil, ErrInvalidShareCode
+		}
+		total.Mul(total, dictionaryLength)
+		total.Add(total, big.NewInt(int64(index)))
+	}
+
+	if total.BitLen() > byteCount*8 {
+		return nil, ErrInvalidShareCode
+	}
+
+	return total.FillBytes(make([]byte, byteCount)), nil
+}
+
 func encodeMatchShareCode(match MatchInformation) string {
 	bytes := make([]byte, 18)
 
@@ -43,3 +71,21 @@ func encodeMatchShareCode(match MatchInformation) string {
 
 	return bytesToShareCode(bytes)
 }
+
+// Exported for pkg/sharecode.
+func EncodeMatchShareCode(match MatchInformation) string {
+	return encodeMatchShareCode(match)
+}
+
+func DecodeMatchShareCode(shareCode string) (MatchInformation, error) {
+	bytes, err := shareCodeToBytes(shareCode, 18)
+	if err != nil {
+		return MatchInformation{}, err
+	}
+
+	return MatchInformation{
+		MatchId:       binary.LittleEndian.Uint64(bytes[0:8]),
+		ReservationId: binary.LittleEndian.Uint64(bytes[8:16]),
+		TvPort:        uint32(binary.LittleEndian.Uint16(bytes[16:18])),
+	}, nil
+}
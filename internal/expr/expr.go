@@ -0,0 +1,472 @@
+// Package expr is a small expression language evaluated against struct values, it's used by metrics definitions.
+//
+// Supported syntax:
+//   - Fields of the struct by their Go or JSON name: Distance, killerSteamId
+//   - Literals: 2000, 1.5, "AWP", 'AWP', true, false
+//   - Operators by ascending precedence: ||, &&, == != < <= > >=, + -, * / %, ! and unary -
+//   - Parentheses
+//
+// Expressions are type checked when they are parsed, strings can only be compared and numbers are compared and
+// computed as float64 unless both operands are integers.
+package expr
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+type Kind int
+
+const (
+	KindNumber Kind = iota
+	KindString
+	KindBool
+)
+
+func (kind Kind) String() string {
+	switch kind {
+	case KindNumber:
+		return "number"
+	case KindString:
+		return "string"
+	default:
+		return "bool"
+	}
+}
+
+type Value struct {
+	Kind    Kind
+	IsInt   bool // Number without decimals, Int is set instead of Float
+	Int     int64
+	Float   float64
+	String  string
+	Boolean bool
+}
+
+func (value Value) Number() float64 {
+	if value.IsInt {
+		return float64(value.Int)
+	}
+
+	return value.Float
+}
+
+// Formats the value as the CSV exports do.
+func (value Value) Text() string {
+	switch value.Kind {
+	case KindString:
+		return value.String
+	case KindBool:
+		if value.Boolean {
+			return "1"
+		}
+		return "0"
+	default:
+		if value.IsInt {
+			return fmt.Sprint(value.Int)
+		}
+		return fmt.Sprintf("%f", value.Float)
+	}
+}
+
+type node interface {
+	kind() Kind
+	eval(element reflect.Value) Value
+}
+
+type Expression struct {
+	source string
+	root   node
+}
+
+func (expression *Expression) Kind() Kind {
+	return expression.root.kind()
+}
+
+func (expression *Expression) String() string {
+	return expression.source
+}
+
+// Evaluates the expression against element, a struct or a pointer to a struct of the type given to Parse.
+func (expression *Expression) Eval(element any) Value {
+	value := reflect.ValueOf(element)
+	for value.Kind() == reflect.Pointer {
+		value = value.Elem()
+	}
+
+	return expression.root.eval(value)
+}
+
+// Parses an expression which refers to fields of elementType (a struct or a pointer to a struct).
+func Parse(source string, elementType reflect.Type) (*Expression, error) {
+	for elementType.Kind() == reflect.Pointer {
+		elementType = elementType.Elem()
+	}
+	if elementType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expressions can only be evaluated against structs, got %s", elementType)
+	}
+
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens, elementType: elementType}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if token := p.peek(); token.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", token.text, token.position)
+	}
+
+	return &Expression{source: source, root: root}, nil
+}
+
+type parser struct {
+	tokens      []token
+	index       int
+	elementType reflect.Type
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.index]
+}
+
+func (p *parser) next() token {
+	token := p.tokens[p.index]
+	if token.kind != tokenEOF {
+		p.index++
+	}
+
+	return token
+}
+
+func (p *parser) acceptOperator(operators ...string) (string, bool) {
+	token := p.peek()
+	if token.kind != tokenOperator {
+		return "", false
+	}
+	for _, operator := range operators {
+		if token.text == operator {
+			p.index++
+			return operator, true
+		}
+	}
+
+	return "", false
+}
+
+func (p *parser) parseOr() (node, error) {
+	return p.parseBinary(p.parseAnd, "||")
+}
+
+func (p *parser) parseAnd() (node, error) {
+	return p.parseBinary(p.parseComparison, "&&")
+}
+
+func (p *parser) parseComparison() (node, error) {
+	return p.parseBinary(p.parseAdditive, "==", "!=", "<=", ">=", "<", ">")
+}
+
+func (p *parser) parseAdditive() (node, error) {
+	return p.parseBinary(p.parseMultiplicative, "+", "-")
+}
+
+func (p *parser) parseMultiplicative() (node, error) {
+	return p.parseBinary(p.parseUnary, "*", "/", "%")
+}
+
+func (p *parser) parseBinary(parseOperand func() (node, error), operators ...string) (node, error) {
+	left, err := parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		position := p.peek().position
+		operator, ok := p.acceptOperator(operators...)
+		if !ok {
+			return left, nil
+		}
+		right, err := parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		left, err = newBinaryNode(operator, left, right)
+		if err != nil {
+			return nil, fmt.Errorf("%v at position %d", err, position)
+		}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	position := p.peek().position
+	operator, ok := p.acceptOperator("!", "-")
+	if !ok {
+		return p.parsePrimary()
+	}
+
+	operand, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	if operator == "!" && operand.kind() != KindBool {
+		return nil, fmt.Errorf("operator ! expects a bool, got %s at position %d", operand.kind(), position)
+	}
+	if operator == "-" && operand.kind() != KindNumber {
+		return nil, fmt.Errorf("operator - expects a number, got %s at position %d", operand.kind(), position)
+	}
+
+	return &unaryNode{operator: operator, operand: operand}, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	token := p.next()
+	switch token.kind {
+	case tokenNumber, tokenString:
+		return &literalNode{value: token.value}, nil
+	case tokenIdentifier:
+		switch token.text {
+		case "true", "false":
+			return &literalNode{value: Value{Kind: KindBool, Boolean: token.text == "true"}}, nil
+		}
+		return newFieldNode(p.elementType, token.text)
+	case tokenOperator:
+		if token.text == "(" {
+			expression, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if closing := p.next(); closing.text != ")" {
+				return nil, fmt.Errorf("missing ) at position %d", closing.position)
+			}
+			return expression, nil
+		}
+	case tokenEOF:
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+
+	return nil, fmt.Errorf("unexpected %q at position %d", token.text, token.position)
+}
+
+type literalNode struct {
+	value Value
+}
+
+func (n *literalNode) kind() Kind {
+	return n.value.Kind
+}
+
+func (n *literalNode) eval(element reflect.Value) Value {
+	return n.value
+}
+
+type fieldNode struct {
+	index     int
+	valueKind Kind
+}
+
+// Fields are matched by their Go name or their JSON name, it's case sensitive.
+func newFieldNode(elementType reflect.Type, name string) (node, error) {
+	for index := range elementType.NumField() {
+		field := elementType.Field(index)
+		jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if !field.IsExported() || (field.Name != name && jsonName != name) {
+			continue
+		}
+
+		switch field.Type.Kind() {
+		case reflect.Bool:
+			return &fieldNode{index: index, valueKind: KindBool}, nil
+		case reflect.String:
+			return &fieldNode{index: index, valueKind: KindString}, nil
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			return &fieldNode{index: index, valueKind: KindNumber}, nil
+		default:
+			return nil, fmt.Errorf("field %s of %s has the unsupported type %s", name, elementType.Name(), field.Type)
+		}
+	}
+
+	return nil, fmt.Errorf("unknown field %s in %s", name, elementType.Name())
+}
+
+func (n *fieldNode) kind() Kind {
+	return n.valueKind
+}
+
+func (n *fieldNode) eval(element reflect.Value) Value {
+	field := element.Field(n.index)
+	switch field.Kind() {
+	case reflect.Bool:
+		return Value{Kind: KindBool, Boolean: field.Bool()}
+	case reflect.String:
+		return Value{Kind: KindString, String: field.String()}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return Value{Kind: KindNumber, IsInt: true, Int: field.Int()}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		// Steam IDs fit into an int64.
+		return Value{Kind: KindNumber, IsInt: true, Int: int64(field.Uint())}
+	default:
+		return Value{Kind: KindNumber, Float: field.Float()}
+	}
+}
+
+type unaryNode struct {
+	operator string
+	operand  node
+}
+
+func (n *unaryNode) kind() Kind {
+	return n.operand.kind()
+}
+
+func (n *unaryNode) eval(element reflect.Value) Value {
+	value := n.operand.eval(element)
+	if n.operator == "!" {
+		return Value{Kind: KindBool, Boolean: !value.Boolean}
+	}
+	if value.IsInt {
+		return Value{Kind: KindNumber, IsInt: true, Int: -value.Int}
+	}
+
+	return Value{Kind: KindNumber, Float: -value.Float}
+}
+
+type binaryNode struct {
+	operator  string
+	left      node
+	right     node
+	valueKind Kind
+}
+
+func newBinaryNode(operator string, left node, right node) (node, error) {
+	leftKind, rightKind := left.kind(), right.kind()
+	switch operator {
+	case "||", "&&":
+		if leftKind != KindBool || rightKind != KindBool {
+			return nil, fmt.Errorf("operator %s expects bools, got %s and %s", operator, leftKind, rightKind)
+		}
+		return &binaryNode{operator: operator, left: left, right: right, valueKind: KindBool}, nil
+	case "==", "!=":
+		if leftKind != rightKind {
+			return nil, fmt.Errorf("cannot compare %s and %s", leftKind, rightKind)
+		}
+		return &binaryNode{operator: operator, left: left, right: right, valueKind: KindBool}, nil
+	case "<", "<=", ">", ">=":
+		if leftKind != rightKind || leftKind == KindBool {
+			return nil, fmt.Errorf("operator %s expects numbers or strings, got %s and %s", operator, leftKind, rightKind)
+		}
+		return &binaryNode{operator: operator, left: left, right: right, valueKind: KindBool}, nil
+	default:
+		if leftKind != KindNumber || rightKind != KindNumber {
+			return nil, fmt.Errorf("operator %s expects numbers, got %s and %s", operator, leftKind, rightKind)
+		}
+		return &binaryNode{operator: operator, left: left, right: right, valueKind: KindNumber}, nil
+	}
+}
+
+func (n *binaryNode) kind() Kind {
+	return n.valueKind
+}
+
+func (n *binaryNode) eval(element reflect.Value) Value {
+	left := n.left.eval(element)
+	switch n.operator {
+	case "||":
+		return Value{Kind: KindBool, Boolean: left.Boolean || n.right.eval(element).Boolean}
+	case "&&":
+		return Value{Kind: KindBool, Boolean: left.Boolean && n.right.eval(element).Boolean}
+	}
+
+	right := n.right.eval(element)
+	switch n.operator {
+	case "==", "!=", "<", "<=", ">", ">=":
+		return Value{Kind: KindBool, Boolean: compare(n.operator, left, right)}
+	}
+
+	if left.IsInt && right.IsInt && n.operator != "/" {
+		switch n.operator {
+		case "+":
+			return Value{Kind: KindNumber, IsInt: true, Int: left.Int + right.Int}
+		case "-":
+			return Value{Kind: KindNumber, IsInt: true, Int: left.Int - right.Int}
+		case "*":
+			return Value{Kind: KindNumber, IsInt: true, Int: left.Int * right.Int}
+		case "%":
+			if right.Int == 0 {
+				return Value{Kind: KindNumber, IsInt: true}
+			}
+			return Value{Kind: KindNumber, IsInt: true, Int: left.Int % right.Int}
+		}
+	}
+
+	leftNumber, rightNumber := left.Number(), right.Number()
+	var result float64
+	switch n.operator {
+	case "+":
+		result = leftNumber + rightNumber
+	case "-":
+		result = leftNumber - rightNumber
+	case "*":
+		result = leftNumber * rightNumber
+	case "/", "%":
+		// Avoid infinite values in the exports.
+		if rightNumber == 0 {
+			return Value{Kind: KindNumber}
+		}
+		if n.operator == "/" {
+			result = leftNumber / rightNumber
+		} else {
+			result = math.Mod(leftNumber, rightNumber)
+		}
+	}
+
+	return Value{Kind: KindNumber, Float: result}
+}
+
+func compare(operator string, left Value, right Value) bool {
+	var comparison int
+	switch {
+	case left.Kind == KindString:
+		comparison = strings.Compare(left.String, right.String)
+	case left.Kind == KindBool:
+		if left.Boolean != right.Boolean {
+			comparison = 1
+		}
+	case left.IsInt && right.IsInt:
+		comparison = compareOrdered(left.Int, right.Int)
+	default:
+		comparison = compareOrdered(left.Number(), right.Number())
+	}
+
+	switch operator {
+	case "==":
+		return comparison == 0
+	case "!=":
+		return comparison != 0
+	case "<":
+		return comparison < 0
+	case "<=":
+		return comparison <= 0
+	case ">":
+		return comparison > 0
+	default:
+		return comparison >= 0
+	}
+}
+
+func compareOrdered[T int64 | float64](left T, right T) int {
+	switch {
+	case left < right:
+		return -1
+	case left > right:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,85 @@
+package expr
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testWeaponName string
+
+type testKill struct {
+	Distance        float32        `json:"distance"`
+	WeaponName      testWeaponName `json:"weaponName"`
+	KillerSteamID64 uint64         `json:"killerSteamId"`
+	IsHeadshot      bool           `json:"isHeadshot"`
+	HealthDamage    int            `json:"healthDamage"`
+	Victims         []string       `json:"victims"`
+}
+
+var kill = &testKill{
+	Distance:        2500.5,
+	WeaponName:      "AWP",
+	KillerSteamID64: 76561198000000001,
+	IsHeadshot:      false,
+	HealthDamage:    100,
+}
+
+func TestEval(t *testing.T) {
+	samples := []struct {
+		source   string
+		expected string
+	}{
+		{`Distance > 2000 && WeaponName == "AWP"`, "1"},
+		{`distance > 2000 && weaponName == 'AK-47'`, "0"},
+		{`!IsHeadshot || Distance < 10`, "1"},
+		{`KillerSteamID64 == 76561198000000001`, "1"},
+		{`KillerSteamID64 == 76561198000000002`, "0"},
+		{`HealthDamage + 20 * 2`, "140"},
+		{`(HealthDamage + 20) * 2`, "240"},
+		{`HealthDamage / 8`, "12.500000"},
+		{`HealthDamage % 7`, "2"},
+		{`HealthDamage / 0`, "0.000000"},
+		{`-HealthDamage`, "-100"},
+		{`WeaponName`, "AWP"},
+		{`WeaponName >= "AK-47"`, "1"},
+		{`true != false`, "1"},
+		{`1.5 * 2`, "3.000000"},
+	}
+
+	for _, sample := range samples {
+		expression, err := Parse(sample.source, reflect.TypeOf(kill))
+		if err != nil {
+			t.Errorf("expected %q to be parsed got %v", sample.source, err)
+			continue
+		}
+		value := expression.Eval(kill)
+		if value.Text() != sample.expected {
+			t.Errorf("expected %q to be %s got %s", sample.source, sample.expected, value.Text())
+		}
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	samples := []string{
+		``,
+		`Unknown > 1`,
+		`Victims == 1`,
+		`WeaponName == 1`,
+		`Distance && true`,
+		`!Distance`,
+		`-WeaponName`,
+		`IsHeadshot < true`,
+		`WeaponName + "a"`,
+		`(Distance > 1`,
+		`Distance > 1)`,
+		`"AWP`,
+		`Distance # 1`,
+		`1.2.3`,
+	}
+
+	for _, source := range samples {
+		if _, err := Parse(source, reflect.TypeOf(kill)); err == nil {
+			t.Errorf("expected %q to be invalid", source)
+		}
+	}
+}
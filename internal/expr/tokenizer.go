@@ -0,0 +1,93 @@
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenIdentifier
+	tokenOperator
+)
+
+type token struct {
+	kind     tokenKind
+	text     string
+	value    Value // Literal value of numbers and strings
+	position int
+}
+
+// Longest operators first.
+var operators = []string{"||", "&&", "==", "!=", "<=", ">=", "<", ">", "+", "-", "*", "/", "%", "!", "(", ")"}
+
+func isDigit(char byte) bool {
+	return char >= '0' && char <= '9'
+}
+
+func isIdentifierChar(char byte) bool {
+	return char == '_' || isDigit(char) || (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z')
+}
+
+func tokenize(source string) ([]token, error) {
+	tokens := []token{}
+	for position := 0; position < len(source); {
+		char := source[position]
+		switch {
+		case char == ' ' || char == '\t' || char == '\n' || char == '\r':
+			position++
+		case isDigit(char):
+			end := position
+			for end < len(source) && (isDigit(source[end]) || source[end] == '.') {
+				end++
+			}
+			text := source[position:end]
+			value := Value{Kind: KindNumber}
+			if integer, err := strconv.ParseInt(text, 10, 64); err == nil {
+				value.IsInt = true
+				value.Int = integer
+			} else if number, err := strconv.ParseFloat(text, 64); err == nil {
+				value.Float = number
+			} else {
+				return nil, fmt.Errorf("invalid number %q at position %d", text, position)
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: text, value: value, position: position})
+			position = end
+		case char == '"' || char == '\'':
+			end := strings.IndexByte(source[position+1:], char)
+			if end == -1 {
+				return nil, fmt.Errorf("unterminated string at position %d", position)
+			}
+			text := source[position+1 : position+1+end]
+			tokens = append(tokens, token{kind: tokenString, text: text, value: Value{Kind: KindString, String: text}, position: position})
+			position += end + 2
+		case isIdentifierChar(char):
+			end := position
+			for end < len(source) && isIdentifierChar(source[end]) {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenIdentifier, text: source[position:end], position: position})
+			position = end
+		default:
+			operator := ""
+			for _, candidate := range operators {
+				if strings.HasPrefix(source[position:], candidate) {
+					operator = candidate
+					break
+				}
+			}
+			if operator == "" {
+				return nil, fmt.Errorf("unexpected character %q at position %d", char, position)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: operator, position: position})
+			position += len(operator)
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, position: len(source)}), nil
+}
@@ -0,0 +1,170 @@
+package ogg
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+const (
+	headerTypeBeginningOfStream = 0x02
+	headerTypeEndOfStream       = 0x04
+)
+
+var crcTable = func() [256]uint32 {
+	var table [256]uint32
+	for i := range table {
+		crc := uint32(i) << 24
+		for range 8 {
+			if crc&0x80000000 != 0 {
+				crc = crc<<1 ^ 0x04c11db7
+			} else {
+				crc <<= 1
+			}
+		}
+		table[i] = crc
+	}
+
+	return table
+}()
+
+func checksum(data []byte) uint32 {
+	var crc uint32
+	for _, b := range data {
+		crc = crc<<8 ^ crcTable[byte(crc>>24)^b]
+	}
+
+	return crc
+}
+
+// OpusWriter writes raw Opus packets into an Ogg Opus stream (RFC 7845), one packet per page.
+type OpusWriter struct {
+	writer        io.Writer
+	serial        uint32
+	pageSequence  uint32
+	granule       uint64
+	pendingPacket []byte
+}
+
+func NewOpusWriter(writer io.Writer, serial uint32, sampleRate uint32, channelCount byte) (*OpusWriter, error) {
+	opusWriter := &OpusWriter{
+		writer: writer,
+		serial: serial,
+	}
+
+	head := make([]byte, 19)
+	copy(head, "OpusHead")
+	head[8] = 1 // Version
+	head[9] = channelCount
+	binary.LittleEndian.PutUint16(head[10:], 0) // Pre-skip
+	binary.LittleEndian.PutUint32(head[12:], sampleRate)
+	binary.LittleEndian.PutUint16(head[16:], 0) // Output gain
+	head[18] = 0                                // Channel mapping family
+	if err := opusWriter.writePage(head, 0, headerTypeBeginningOfStream); err != nil {
+		return nil, err
+	}
+
+	vendor := "cs-demo-analyzer"
+	tags := make([]byte, 8+4+len(vendor)+4)
+	copy(tags, "OpusTags")
+	binary.LittleEndian.PutUint32(tags[8:], uint32(len(vendor)))
+	copy(tags[12:], vendor)
+	binary.LittleEndian.PutUint32(tags[12+len(vendor):], 0) // User comment count
+	if err := opusWriter.writePage(tags, 0, 0); err != nil {
+		return nil, err
+	}
+
+	return opusWriter, nil
+}
+
+func (opusWriter *OpusWriter) writePage(packet []byte, granule uint64, headerType byte) error {
+	if len(packet) >= 255*255 {
+		return errors.New("opus packet too large to fit in a single ogg page")
+	}
+
+	segmentCount := len(packet)/255 + 1
+	page := make([]byte, 27+segmentCount, 27+segmentCount+len(packet))
+	copy(page, "OggS")
+	page[4] = 0 // Version
+	page[5] = headerType
+	binary.LittleEndian.PutUint64(page[6:], granule)
+	binary.LittleEndian.PutUint32(page[14:], opusWriter.serial)
+	binary.LittleEndian.PutUint32(page[18:], opusWriter.pageSequence)
+	page[26] = byte(segmentCount)
+	for i := range segmentCount - 1 {
+		page[27+i] = 255
+	}
+	page[27+segmentCount-1] = byte(len(packet) % 255)
+	page = append(page, packet...)
+	binary.LittleEndian.PutUint32(page[22:], checksum(page))
+
+	opusWriter.pageSequence++
+	_, err := opusWriter.writer.Write(page)
+
+	return err
+}
+
+// The last page of the stream must be flagged, packets are written with a delay of 1 packet to know which one is the last.
+func (opusWriter *OpusWriter) WritePacket(packet []byte) error {
+	if len(packet) == 0 {
+		return nil
+	}
+
+	if opusWriter.pendingPacket != nil {
+		if err := opusWriter.flushPendingPacket(0); err != nil {
+			return err
+		}
+	}
+	opusWriter.pendingPacket = append([]byte(nil), packet...)
+
+	return nil
+}
+
+func (opusWriter *OpusWriter) flushPendingPacket(headerType byte) error {
+	opusWriter.granule += uint64(OpusPacketSampleCount(opusWriter.pendingPacket))
+	err := opusWriter.writePage(opusWriter.pendingPacket, opusWriter.granule, headerType)
+	opusWriter.pendingPacket = nil
+
+	return err
+}
+
+func (opusWriter *OpusWriter) Close() error {
+	if opusWriter.pendingPacket == nil {
+		return nil
+	}
+
+	return opusWriter.flushPendingPacket(headerTypeEndOfStream)
+}
+
+// OpusPacketSampleCount returns the number of samples per channel at 48kHz (the Opus granule position clock) of an Opus packet based on its TOC byte.
+// https://datatracker.ietf.org/doc/html/rfc6716#section-3.1
+func OpusPacketSampleCount(packet []byte) int {
+	if len(packet) == 0 {
+		return 0
+	}
+
+	toc := packet[0]
+	config := int(toc >> 3)
+	var frameSize int
+	switch {
+	case config < 12: // SILK, 10, 20, 40 or 60 ms
+		frameSize = []int{480, 960, 1920, 2880}[config%4]
+	case config < 16: // Hybrid, 10 or 20 ms
+		frameSize = []int{480, 960}[config%2]
+	default: // CELT, 2.5, 5, 10 or 20 ms
+		frameSize = []int{120, 240, 480, 960}[config%4]
+	}
+
+	frameCount := 1
+	switch toc & 0x03 {
+	case 1, 2:
+		frameCount = 2
+	case 3:
+		if len(packet) < 2 {
+			return 0
+		}
+		frameCount = int(packet[1] & 0x3F)
+	}
+
+	return frameSize * frameCount
+}
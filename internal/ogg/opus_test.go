@@ -0,0 +1,54 @@
+package ogg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestOpusPacketSampleCount(t *testing.T) {
+	samples := map[string]struct {
+		packet   []byte
+		expected int
+	}{
+		"celt 20ms":              {packet: []byte{0xF8, 0x00}, expected: 960},
+		"silk 60ms":              {packet: []byte{0x18, 0x00}, expected: 2880},
+		"celt 10ms 2 frames":     {packet: []byte{0xF1, 0x00}, expected: 960},
+		"celt 20ms code 3 x 3":   {packet: []byte{0xFB, 0x03}, expected: 2880},
+		"code 3 missing counter": {packet: []byte{0xFB}, expected: 0},
+	}
+
+	for name, sample := range samples {
+		if count := OpusPacketSampleCount(sample.packet); count != sample.expected {
+			t.Fatalf("%s: expected %d samples, got %d", name, sample.expected, count)
+		}
+	}
+}
+
+func TestOpusWriter(t *testing.T) {
+	var buffer bytes.Buffer
+	writer, err := NewOpusWriter(&buffer, 1, 48000, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		if err := writer.WritePacket([]byte{0xF8, 0x01, 0x02}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	pages := bytes.Split(buffer.Bytes(), []byte("OggS"))[1:]
+	if len(pages) != 4 {
+		t.Fatalf("expected 4 pages, got %d", len(pages))
+	}
+	// Offsets are relative to the end of the capture pattern.
+	if pages[0][1] != headerTypeBeginningOfStream || pages[3][1] != headerTypeEndOfStream {
+		t.Fatalf("unexpected header types %d and %d", pages[0][1], pages[3][1])
+	}
+	if granule := binary.LittleEndian.Uint64(pages[3][2:]); granule != 1920 {
+		t.Fatalf("expected last granule position to be 1920, got %d", granule)
+	}
+}
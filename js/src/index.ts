@@ -10,6 +10,11 @@ export type Options = {
   source?: DemoSource;
   analyzePositions?: boolean;
   minify?: boolean; // JSON only
+  setupOffsets?: number[]; // Seconds after the freeze time end at which teams setup are captured
+  voiceOutputFolderPath?: string; // Folder where players voice is written as Ogg Opus files, CS2 only
+  timeout?: string; // Go duration such as "2m", the analysis fails if it takes longer
+  signal?: AbortSignal; // Kills the analysis process when aborted
+  onProgress?: (progress: number, round: number) => void; // progress is between 0 and 1
   onStart?: (command: string) => void;
   onStdout?: (data: string) => void;
   onStderr?: (data: string) => void;
@@ -24,6 +29,11 @@ export async function analyzeDemo({
   source,
   analyzePositions,
   minify,
+  setupOffsets,
+  voiceOutputFolderPath,
+  timeout,
+  signal,
+  onProgress,
   onStart,
   onStdout,
   onStderr,
@@ -49,25 +59,64 @@ export async function analyzeDemo({
     if (minify) {
       args.push('-minify');
     }
+    if (setupOffsets && setupOffsets.length > 0) {
+      args.push(`-setup-offsets="${setupOffsets.join(',')}"`);
+    }
+    if (voiceOutputFolderPath) {
+      args.push(`-voice-output="${voiceOutputFolderPath}"`);
+    }
+    if (timeout) {
+      args.push(`-timeout="${timeout}"`);
+    }
+    if (onProgress) {
+      args.push('-progress');
+    }
     const command = args.join(' ');
     if (onStart) {
       onStart(command);
     }
 
-    const child = exec(command, { windowsHide: true, maxBuffer: undefined });
+    const child = exec(command, { windowsHide: true, maxBuffer: undefined, signal });
     if (onStdout) {
       child.stdout?.on('data', (data: string) => {
         onStdout(data);
       });
     }
 
-    if (onStderr) {
+    // Progress lines are JSON objects such as {"progress":0.42,"round":12}, other lines are forwarded to onStderr.
+    let pendingStderr = '';
+    if (onStderr || onProgress) {
       child.stderr?.on('data', (data: string) => {
-        onStderr(data);
+        if (!onProgress) {
+          onStderr?.(data);
+          return;
+        }
+
+        const lines = (pendingStderr + data).split('\n');
+        pendingStderr = lines.pop() ?? '';
+        const otherLines: string[] = [];
+        for (const line of lines) {
+          const match = line.match(/^\{"progress":([0-9.]+),"round":(\d+)\}$/);
+          if (match) {
+            onProgress(Number(match[1]), Number(match[2]));
+          } else {
+            otherLines.push(line);
+          }
+        }
+        if (otherLines.length > 0) {
+          onStderr?.(otherLines.join('\n') + '\n');
+        }
       });
     }
 
+    child.on('error', (error) => {
+      reject(error);
+    });
+
     child.on('exit', (code: number) => {
+      if (pendingStderr !== '') {
+        onStderr?.(pendingStderr);
+      }
       if (onEnd) {
         onEnd(code);
       }
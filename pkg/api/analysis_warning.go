@@ -0,0 +1,29 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/akiver/cs-demo-analyzer/pkg/api/constants"
+)
+
+// Anomaly detected during the analysis, usually the related event has been ignored or its data may be inaccurate.
+type AnalysisWarning struct {
+	Frame       int                   `json:"frame"`
+	Tick        int                   `json:"tick"`
+	RoundNumber int                   `json:"roundNumber"`
+	Kind        constants.WarningKind `json:"kind"`
+	Message     string                `json:"message"`
+}
+
+// Records a warning in the match and logs it.
+func (analyzer *Analyzer) warn(kind constants.WarningKind, format string, args ...any) {
+	warning := &AnalysisWarning{
+		Frame:       analyzer.parser.CurrentFrame(),
+		Tick:        analyzer.currentTick(),
+		RoundNumber: analyzer.currentRound.Number,
+		Kind:        kind,
+		Message:     fmt.Sprintf(format, args...),
+	}
+	analyzer.match.Warnings = append(analyzer.match.Warnings, warning)
+	analyzer.logger.Warn(warning.Message, "kind", kind, "tick", warning.Tick, "round", warning.RoundNumber)
+}
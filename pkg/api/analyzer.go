@@ -1,29 +1,36 @@
 package api
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/akiver/cs-demo-analyzer/internal/converters"
 	d "github.com/akiver/cs-demo-analyzer/internal/demo"
+	"github.com/akiver/cs-demo-analyzer/internal/filepath"
 	"github.com/akiver/cs-demo-analyzer/internal/slice"
 	"github.com/akiver/cs-demo-analyzer/internal/strings"
 	"github.com/akiver/cs-demo-analyzer/pkg/api/constants"
 	"github.com/akiver/cs-demo-analyzer/pkg/api/funData"
+	"github.com/akiver/cs-demo-analyzer/pkg/api/plugin"
 	"github.com/golang/geo/r3"
 	dem "github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs"
 	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
 	events "github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/events"
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/msg"
 	st "github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/sendtables"
 	"github.com/oklog/ulid/v2"
 )
 
 const (
-	// Maximum number of seconds between a teammate death and a possible revenge kill to be considered as a trade kill.
+	// Default maximum number of seconds between a teammate death and a possible revenge kill to be considered as a trade kill.
 	tradeKillDelaySeconds = 5
-	// Number of seconds that players equipment value will be computed after the end of freezetimes.
+	// Default number of seconds that players equipment value will be computed after the end of freezetimes.
 	// It's not done at the end of freezetime because players are still able to buy a few seconds after it.
 	// Using the seconds from mp_buytime which is 20 seconds may leads to inaccurate results since during this timelapse
 	// players may have throw grenades, been killed...
@@ -74,14 +81,97 @@ type Analyzer struct {
 	fallDamageFrameBySteamID      map[uint64]int
 	pendingCS2FallDamages         map[int][]*Damage
 	pendingBulletDamageByKey      map[damageMatchFrameKey][]int
+	// Offsets in seconds after the freeze time end at which teams setup are captured, sorted in ascending order.
+	setupSnapshotOffsets []float64
+	// Index of the next offset to capture for the current round.
+	setupSnapshotIndex int
+	// Bomb carrier detected during the previous frame, nil when the bomb is on the ground or planted.
+	bombCarrier *common.Player
+	// Last player who carried the bomb during the current round.
+	lastBombCarrier *common.Player
+	// Tick at which the bomb has been dropped, -1 if it's not on the ground.
+	bombDroppedTick int
+	// Tick of the previous frame used to compute the bomb carry time, -1 at the beginning of a round.
+	lastBombCarryTick int
+	isBombPlanted     bool
+	// Players who disconnected, used to detect reconnections.
+	disconnectedPlayers map[uint64]bool
+	// Vote in progress, nil if there is no vote.
+	currentVote          *Vote
+	voteControllerEntity st.Entity
+	// Last voice activity of each player, extended while they keep speaking.
+	voiceActivityBySteamID map[uint64]*VoiceActivity
+	isLoadoutCaptured      bool
+	logger                 *slog.Logger
+	config                 AnalyzerConfig
 }
 
 type AnalyzeDemoOptions struct {
 	IncludePositions bool
 	Source           constants.DemoSource
+	// Seconds after the end of the freeze time at which teams setup are captured, default to DefaultSetupSnapshotOffsets.
+	SetupSnapshotOffsets []float64
+	// Called from the parsing goroutine with the fraction of the demo parsed (0 to 1) and the current round number.
+	// It's called at most every 1% and when a new round starts.
+	OnProgress func(fraction float64, round int)
+	// Warnings recorded in Match.Warnings are logged at the warn level, nothing is logged if nil.
+	Logger *slog.Logger
+	// Heuristics thresholds, DefaultAnalyzerConfig is used if nil.
+	Config *AnalyzerConfig
+	// Registered in order once the built-in handlers are registered, their tables are in Match.CustomTables.
+	Plugins []plugin.Plugin
+	// Derived metrics computed once the analysis is done, see LoadMetricDefinitions.
+	Metrics []MetricDefinition
+	// Set by the export functions only, the analysis alone doesn't write files.
+	voiceOutputFolder string
 }
 
-func analyzeDemo(demoPath string, options AnalyzeDemoOptions) (*Match, error) {
+type demoInput struct {
+	reader       io.Reader
+	name         string
+	matchInfo    *msg.CDataGCCStrike15V2_MatchInfo
+	matchInfoErr error     // The .info file exists but can't be read
+	date         time.Time // Used as the match date when the .info file is not available
+	// Fraction of the input already read, nil if unknown.
+	readFraction func() float64
+}
+
+func analyzeDemo(ctx context.Context, demoPath string, options AnalyzeDemoOptions) (*Match, error) {
+	file, err := os.Open(demoPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, newError(ErrorCodeDemoNotFound, fmt.Sprintf("demo file %q not found", demoPath), nil)
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	stats, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	matchInfo, matchInfoErr := d.GetMatchInfo(demoPath)
+
+	return analyzeDemoFromReader(ctx, demoInput{
+		reader:       file,
+		name:         filepath.GetAbsoluteFilePath(demoPath),
+		matchInfo:    matchInfo,
+		matchInfoErr: matchInfoErr,
+		date:         stats.ModTime(),
+		readFraction: fileReadFraction(file, stats.Size()),
+	}, options)
+}
+
+// The reader is consumed only once, the demo header is peeked before parsing.
+// Panics are recovered and returned as Internal errors.
+func analyzeDemoFromReader(ctx context.Context, input demoInput, options AnalyzeDemoOptions) (_ *Match, err error) {
+	defer recoverAnalysisPanic(&err)
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	if options.Source != "" {
 		err := ValidateDemoSource(options.Source)
 		if err != nil {
@@ -89,24 +179,45 @@ func analyzeDemo(demoPath string, options AnalyzeDemoOptions) (*Match, error) {
 		}
 	}
 
-	demo, err := d.GetDemoFromPath(demoPath)
+	config := DefaultAnalyzerConfig()
+	if options.Config != nil {
+		config = *options.Config
+		if err := config.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	metrics, err := compileMetrics(options.Metrics)
 	if err != nil {
 		return nil, err
 	}
 
-	file, err := os.Open(demoPath)
+	demoReader, err := d.NewReader(input.reader)
 	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return nil, fmt.Errorf("demo file %q not found", demoPath)
-		}
 		return nil, err
 	}
-	defer file.Close()
+	defer demoReader.Close()
+
+	demo, err := d.GetDemoFromReader(demoReader, input.name, input.matchInfo)
+	if err != nil {
+		return nil, newError(ErrorCodeCorruptedDemo, ErrCorruptedDemo.Message, err)
+	}
+	// The parser supports only CS2 demos.
+	switch demo.Filestamp {
+	case "PBDEMS2":
+	case "HL2DEMO":
+		return nil, ErrGameNotSupported
+	default:
+		return nil, newError(ErrorCodeCorruptedDemo, fmt.Sprintf("invalid demo file stamp %q", demo.Filestamp), nil)
+	}
+	if demo.Date.IsZero() {
+		demo.Date = input.date
+	}
 
 	parserConfig := dem.DefaultParserConfig
 	parserConfig.DisableMimicSource1Events = demo.Type == constants.DemoTypePOV
 
-	parser := dem.NewParserWithConfig(file, parserConfig)
+	parser := dem.NewParserWithConfig(demoReader, parserConfig)
 	defer parser.Close()
 
 	source := options.Source
@@ -115,10 +226,19 @@ func analyzeDemo(demoPath string, options AnalyzeDemoOptions) (*Match, error) {
 	}
 
 	if demo.IsSource2() && demo.Type == constants.DemoTypePOV {
-		return nil, errors.New("cs2 pov demos are not supported (CS2POVDemosNotSupported)")
+		return nil, ErrPOVNotSupported
+	}
+
+	logger := options.Logger
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
 	}
+	logger = logger.With("demo", demo.FileName)
 
 	match := newMatch(source, demo)
+	match.AnalyzerConfig = &config
+	match.voiceWriters = newVoiceOggWriters(options.voiceOutputFolder)
+	defer match.voiceWriters.close()
 
 	analyzer := &Analyzer{
 		parser:                        parser,
@@ -139,6 +259,13 @@ func analyzeDemo(demoPath string, options AnalyzeDemoOptions) (*Match, error) {
 		pendingCS2FallDamages:         make(map[int][]*Damage),
 		pendingBulletDamageByKey:      make(map[damageMatchFrameKey][]int),
 		postProcess:                   defaultPostProcess,
+		setupSnapshotOffsets:          sortedSetupSnapshotOffsets(options.SetupSnapshotOffsets),
+		bombDroppedTick:               -1,
+		lastBombCarryTick:             -1,
+		disconnectedPlayers:           make(map[uint64]bool),
+		voiceActivityBySteamID:        make(map[uint64]*VoiceActivity),
+		logger:                        logger,
+		config:                        config,
 	}
 
 	analyzer.currentRound = &Round{
@@ -154,6 +281,11 @@ func analyzeDemo(demoPath string, options AnalyzeDemoOptions) (*Match, error) {
 		TeamBSide:          *match.TeamB.CurrentSide,
 	}
 
+	if input.matchInfoErr != nil {
+		analyzer.warn(constants.WarningKindInvalidInfoFile, "%v", input.matchInfoErr)
+	}
+	logger.Debug("analyzing demo", "source", source, "game", match.Game, "map", match.MapName)
+
 	analyzer.registerCommonHandlers(options.IncludePositions)
 
 	switch source {
@@ -172,14 +304,13 @@ func analyzeDemo(demoPath string, options AnalyzeDemoOptions) (*Match, error) {
 	case constants.DemoSourceEsportal:
 		createEsportalAnalyzer(analyzer)
 	case constants.DemoSourceCEVO:
-		return nil, errors.New("cevo demos are not supported (CevoNotSupported)")
+		return nil, newError(ErrorCodeSourceNotSupported, "cevo demos are not supported", nil)
 	case constants.DemoSourceFastcup:
 		createFastcupAnalyzer(analyzer)
 	case constants.DemoSourceFiveEPlay:
 		createFiveEPlayAnalyzer(analyzer)
 	case constants.DemoSourceGamersclub:
-		// Looks like they use an eBot fork but rounds are not detected properly.
-		return nil, errors.New("gamersclub demos are not supported (GamersClubNotSupported)")
+		createGamersclubAnalyzer(analyzer)
 	case constants.DemoSourceMatchZy:
 		createMatchZyAnalyzer(analyzer)
 	case constants.DemoSourcePopFlash:
@@ -187,21 +318,50 @@ func analyzeDemo(demoPath string, options AnalyzeDemoOptions) (*Match, error) {
 		// Even latest CSGO demos from PopFlash may not really work because their recording system has probably changed
 		// and may not be compatible with the Valve one anymore (used to be the V2).
 		if demo.IsSource2() {
-			return nil, errors.New("cs2 PopFlash demos are not supported (PopFlashNotSupported)")
+			return nil, newError(ErrorCodeSourceNotSupported, "cs2 PopFlash demos are not supported", nil)
 		}
 		createValveAnalyzer(analyzer)
 	case constants.DemoSourceValve, constants.DemoSourcePerfectWorld, constants.DemoSourceESL:
 		createValveAnalyzer(analyzer)
 	default:
-		return nil, errors.New("unknown demo source, please specify the source with the -source flag (UnknownSource)")
+		return nil, ErrUnknownSource
+	}
+
+	if err := analyzer.registerPlugins(options.Plugins); err != nil {
+		return nil, err
 	}
 
+	progress := &progressReporter{
+		onProgress: options.OnProgress,
+		fraction: func() float64 {
+			if fraction := parser.Progress(); fraction > 0 {
+				return float64(fraction)
+			}
+			if input.readFraction != nil {
+				return input.readFraction()
+			}
+
+			return 0
+		},
+	}
+	if progress.onProgress != nil {
+		parser.RegisterEventHandler(func(event events.FrameDone) {
+			progress.update(analyzer.currentRound.Number)
+		})
+	}
+
+	stopCancellation := context.AfterFunc(ctx, parser.Cancel)
+	defer stopCancellation()
+
 	err = parser.ParseToEnd()
+	if ctxErr := ctx.Err(); ctxErr != nil && errors.Is(err, dem.ErrCancelled) {
+		return nil, ctxErr
+	}
 	// Do not stop if the demo is corrupted, usually the error occurs at the end of the parsing.
 	// Depending on how far we were able to parse the demo we may still have data.
 	isCorruptedDemo := errors.Is(err, dem.ErrUnexpectedEndOfDemo)
 	if err != nil && !isCorruptedDemo {
-		return nil, err
+		return nil, newError(ErrorCodeCorruptedDemo, ErrCorruptedDemo.Message, err)
 	}
 
 	// Required for CS2 demos, the following data are available only at the end of the parsing.
@@ -215,25 +375,76 @@ func analyzeDemo(demoPath string, options AnalyzeDemoOptions) (*Match, error) {
 
 	analyzer.postProcess(analyzer)
 	match.deleteIncompleteRounds()
+	if err := match.voiceWriters.close(); err != nil {
+		return nil, newError(ErrorCodeInternal, "failed to write voice file", err)
+	}
 	match.computeResultStats()
 
+	if match.gameModeStr == "" && constants.GameModeMapping[match.GameType][match.GameMode] == "" {
+		analyzer.warn(constants.WarningKindUnknownGameMode, "Unknown game mode string for game type %d and game mode %d", match.GameType, match.GameMode)
+	}
+
+	// The checksum depends on the demo size which is known only once the whole stream has been read.
+	size, err := demoReader.Size()
+	if err != nil {
+		return nil, err
+	}
+	demo.SetFileSize(size)
+	match.Checksum = demo.Checksum
+
+	if match.ValveMatchInfo != nil {
+		match.ValveMatchInfo.crossCheck(&match)
+	}
+	match.Validation = newValidationReport(&match)
+	for _, metric := range metrics {
+		match.Metrics = append(match.Metrics, metric.compute(&match))
+	}
+
+	progress.complete(len(match.Rounds))
+	logger.Debug("demo analyzed", "rounds", len(match.Rounds), "warnings", len(match.Warnings))
+
 	return &match, nil
 }
 
 func AnalyzeDemo(demoPath string, options AnalyzeDemoOptions) (*Match, error) {
-	match, err := analyzeDemo(demoPath, options)
+	return AnalyzeDemoContext(context.Background(), demoPath, options)
+}
+
+// The analysis stops and the context error is returned when the context is done.
+func AnalyzeDemoContext(ctx context.Context, demoPath string, options AnalyzeDemoOptions) (*Match, error) {
+	return analyzeDemo(ctx, demoPath, options)
+}
+
+// Analyzes a demo from a stream, it may be compressed with gzip, bzip2, zstd or zip (single entry).
+// name is used as the demo file path and to deduce the demo file name, it's also used to detect the demo source.
+// The .info file is not available, the match date is zero and Valve match info is nil.
+func AnalyzeDemoFromReader(reader io.Reader, name string, options AnalyzeDemoOptions) (*Match, error) {
+	return AnalyzeDemoFromReaderContext(context.Background(), reader, name, options)
+}
 
-	return match, err
+func AnalyzeDemoFromReaderContext(ctx context.Context, reader io.Reader, name string, options AnalyzeDemoOptions) (*Match, error) {
+	return analyzeDemoFromReader(ctx, demoInput{reader: reader, name: name}, options)
 }
 
 type AnalyzeAndExportDemoOptions struct {
-	IncludePositions bool
-	Source           constants.DemoSource
-	Format           constants.ExportFormat
-	MinifyJSON       bool
+	IncludePositions     bool
+	Source               constants.DemoSource
+	Format               constants.ExportFormat
+	MinifyJSON           bool
+	SetupSnapshotOffsets []float64
+	// If set, players voice (CS2 demos recorded with tv_relayvoice) is written into Ogg Opus files per round in this
+	// folder while the demo is parsed.
+	VoiceOutputFolder string
+	OnProgress        func(fraction float64, round int)
+	Logger            *slog.Logger
+	// Return an error if a validation check failed, the match is still exported.
+	Strict  bool
+	Config  *AnalyzerConfig
+	Plugins []plugin.Plugin
+	Metrics []MetricDefinition
 }
 
-func AnalyzeAndExportDemo(demoPath string, outputPath string, options AnalyzeAndExportDemoOptions) error {
+func analyzeAndExportDemo(analyze func(options AnalyzeDemoOptions) (*Match, error), outputPath string, options AnalyzeAndExportDemoOptions) error {
 	var err error
 	if options.Format != "" {
 		err = ValidateExportFormat(options.Format)
@@ -242,9 +453,16 @@ func AnalyzeAndExportDemo(demoPath string, outputPath string, options AnalyzeAnd
 		}
 	}
 
-	match, err := analyzeDemo(demoPath, AnalyzeDemoOptions{
-		IncludePositions: options.IncludePositions,
-		Source:           options.Source,
+	match, err := analyze(AnalyzeDemoOptions{
+		IncludePositions:     options.IncludePositions,
+		Source:               options.Source,
+		SetupSnapshotOffsets: options.SetupSnapshotOffsets,
+		voiceOutputFolder:    options.VoiceOutputFolder,
+		OnProgress:           options.OnProgress,
+		Logger:               options.Logger,
+		Config:               options.Config,
+		Plugins:              options.Plugins,
+		Metrics:              options.Metrics,
 	})
 
 	if err != nil {
@@ -259,8 +477,37 @@ func AnalyzeAndExportDemo(demoPath string, outputPath string, options AnalyzeAnd
 	case "csdm":
 		err = exportMatchForCSDM(match, outputPath)
 	}
+	if err != nil {
+		return err
+	}
+
+	// The match is exported anyway to be able to look at the data.
+	if options.Strict {
+		return match.Validation.err()
+	}
+
+	return nil
+}
 
-	return err
+func AnalyzeAndExportDemo(demoPath string, outputPath string, options AnalyzeAndExportDemoOptions) error {
+	return AnalyzeAndExportDemoContext(context.Background(), demoPath, outputPath, options)
+}
+
+func AnalyzeAndExportDemoContext(ctx context.Context, demoPath string, outputPath string, options AnalyzeAndExportDemoOptions) error {
+	return analyzeAndExportDemo(func(analyzeOptions AnalyzeDemoOptions) (*Match, error) {
+		return analyzeDemo(ctx, demoPath, analyzeOptions)
+	}, outputPath, options)
+}
+
+// Same as AnalyzeAndExportDemo with a stream, see AnalyzeDemoFromReader.
+func AnalyzeAndExportDemoFromReader(reader io.Reader, name string, outputPath string, options AnalyzeAndExportDemoOptions) error {
+	return AnalyzeAndExportDemoFromReaderContext(context.Background(), reader, name, outputPath, options)
+}
+
+func AnalyzeAndExportDemoFromReaderContext(ctx context.Context, reader io.Reader, name string, outputPath string, options AnalyzeAndExportDemoOptions) error {
+	return analyzeAndExportDemo(func(analyzeOptions AnalyzeDemoOptions) (*Match, error) {
+		return AnalyzeDemoFromReaderContext(ctx, reader, name, analyzeOptions)
+	}, outputPath, options)
 }
 
 func (analyzer *Analyzer) currentTick() int {
@@ -281,6 +528,12 @@ func (analyzer *Analyzer) reset() {
 	analyzer.pendingBulletDamageByKey = make(map[damageMatchFrameKey][]int)
 	analyzer.lastGrenadeProjectilePosition = make(map[int64]grenadeProjectilePositionSample)
 	analyzer.chickenEntities = nil
+	analyzer.setupSnapshotIndex = 0
+	analyzer.isLoadoutCaptured = false
+	analyzer.resetBombCarrier()
+	analyzer.disconnectedPlayers = make(map[uint64]bool)
+	analyzer.currentVote = nil
+	analyzer.voiceActivityBySteamID = make(map[uint64]*VoiceActivity)
 	analyzer.clutch1 = nil
 	analyzer.clutch2 = nil
 	analyzer.match.reset()
@@ -308,6 +561,9 @@ func (analyzer *Analyzer) reset() {
 
 func (analyzer *Analyzer) resetCurrentRound() {
 	analyzer.match.resetRound(analyzer.currentRound.Number)
+	analyzer.setupSnapshotIndex = 0
+	analyzer.isLoadoutCaptured = false
+	analyzer.resetBombCarrier()
 	analyzer.createPlayersEconomies()
 	analyzer.initLastPlayersPosition()
 }
@@ -444,6 +700,9 @@ func (analyzer *Analyzer) createRound() {
 	analyzer.fallDamageFrameBySteamID = make(map[uint64]int)
 	analyzer.pendingCS2FallDamages = make(map[int][]*Damage)
 	analyzer.lastGrenadeProjectilePosition = make(map[int64]grenadeProjectilePositionSample)
+	analyzer.setupSnapshotIndex = 0
+	analyzer.isLoadoutCaptured = false
+	analyzer.resetBombCarrier()
 
 	roundNumber := analyzer.currentRound.Number + 1
 
@@ -506,6 +765,7 @@ func (analyzer *Analyzer) handleRoundEnd(winnerTeam common.Team) {
 		analyzer.clutch2.HasWon = winnerTeam == analyzer.clutch2.Side
 	}
 
+	analyzer.registerBombOnGroundAtRoundEnd(analyzer.bombFrameState())
 	analyzer.updatePlayersScores()
 }
 
@@ -596,6 +856,35 @@ func (analyzer *Analyzer) registerCommonHandlers(includePositions bool) {
 
 	parser.RegisterEventHandler(func(event events.PlayerTeamChange) {
 		analyzer.registerPlayer(event.Player, event.NewTeamState)
+		if event.OldTeam == event.NewTeam {
+			return
+		}
+		if sessionEvent := analyzer.registerPlayerSessionEvent(constants.PlayerSessionEventTypeTeamChange, event.Player); sessionEvent != nil {
+			sessionEvent.OldSide = event.OldTeam
+			sessionEvent.NewSide = event.NewTeam
+		}
+	})
+
+	analyzer.registerVoteHandlers()
+
+	parser.RegisterNetMessageHandler(analyzer.registerVoiceData)
+
+	parser.RegisterEventHandler(func(event events.PlayerConnect) {
+		analyzer.registerPlayerSessionEvent(constants.PlayerSessionEventTypeConnect, event.Player)
+	})
+
+	parser.RegisterEventHandler(func(event events.PlayerDisconnected) {
+		analyzer.registerPlayerSessionEvent(constants.PlayerSessionEventTypeDisconnect, event.Player)
+	})
+
+	parser.RegisterEventHandler(func(event events.BotTakenOver) {
+		sessionEvent := analyzer.registerPlayerSessionEvent(constants.PlayerSessionEventTypeBotTakeover, event.Taker)
+		if sessionEvent == nil {
+			return
+		}
+		if bot := event.Taker.ControlledBot(); bot != nil {
+			sessionEvent.BotName = bot.Name
+		}
 	})
 
 	parser.RegisterEventHandler(func(event events.RoundStart) {
@@ -627,7 +916,7 @@ func (analyzer *Analyzer) registerCommonHandlers(includePositions bool) {
 		}
 
 		if event.Killer == nil {
-			fmt.Println(analyzer.currentTick(), "A chicken has been killed but the killer is nil")
+			analyzer.warn(constants.WarningKindMissingEntity, "A chicken has been killed but the killer is nil")
 			return
 		}
 
@@ -807,7 +1096,7 @@ func (analyzer *Analyzer) registerCommonHandlers(includePositions bool) {
 			delete(analyzer.pendingCS2FallDamages, pendingFrame)
 		}
 
-		shouldComputeEconomy := analyzer.lastFreezeTimeEndTick != -1 && analyzer.secondsHasPassedSinceTick(equipmentValueDelaySeconds, analyzer.lastFreezeTimeEndTick)
+		shouldComputeEconomy := analyzer.lastFreezeTimeEndTick != -1 && analyzer.secondsHasPassedSinceTick(analyzer.config.EquipmentValueDelaySeconds, analyzer.lastFreezeTimeEndTick)
 		if shouldComputeEconomy {
 			analyzer.computePlayersEconomies()
 			analyzer.currentRound.computeTeamsEconomy()
@@ -833,6 +1122,11 @@ func (analyzer *Analyzer) registerCommonHandlers(includePositions bool) {
 		}
 		analyzer.pendingFootsteps = nil
 
+		analyzer.takeTeamSetupSnapshots()
+		analyzer.updateBombCarrier()
+		analyzer.updatePlayersRoundPlayed()
+		analyzer.captureLoadouts()
+
 		currentTick := analyzer.currentTick()
 		for _, player := range parser.GameState().Participants().Playing() {
 			// Player position history rotation for velocity calculation.
@@ -852,6 +1146,7 @@ func (analyzer *Analyzer) registerCommonHandlers(includePositions bool) {
 			// the tick actually advances, preserving two distinct position snapshots.
 			// The "last" position is always updated regardless, so it stays current.
 			if match.lastPlayersTick[player.SteamID64] != currentTick {
+				analyzer.updatePlayerRoundMovement(player, match.lastPlayersPosition[player.SteamID64], match.lastPlayersTick[player.SteamID64])
 				match.prevPlayersPosition[player.SteamID64] = match.lastPlayersPosition[player.SteamID64]
 				match.prevPlayersTick[player.SteamID64] = match.lastPlayersTick[player.SteamID64]
 			}
@@ -919,7 +1214,7 @@ func (analyzer *Analyzer) registerCommonHandlers(includePositions bool) {
 
 	missingGameEventDescriptorsWarnCount := 0
 	parser.RegisterEventHandler(func(event events.ParserWarn) {
-		fmt.Println(event)
+		analyzer.warn(constants.WarningKindParser, "%s", event.Message)
 		if event.Type != events.WarnTypeGameEventBeforeDescriptors {
 			return
 		}
@@ -932,7 +1227,7 @@ func (analyzer *Analyzer) registerCommonHandlers(includePositions bool) {
 		// https://github.com/markus-wa/demoinfocs-golang/pull/460
 		missingGameEventDescriptorsWarnCount += 1
 		if missingGameEventDescriptorsWarnCount >= 20 {
-			panic(errors.New("missing game event descriptors (ErrMissingGameEventDescriptors)"))
+			panic(abortAnalysis{err: ErrMissingGameEventDescriptors})
 		}
 	})
 
@@ -961,6 +1256,7 @@ func (analyzer *Analyzer) registerCommonHandlers(includePositions bool) {
 		kill := newKillFromGameEvent(analyzer, event)
 		if kill != nil {
 			match.Kills = append(match.Kills, kill)
+			analyzer.registerBombCarrierKill(kill, analyzer.bombFrameState())
 		}
 
 		// Calculate wasted utility value for the victim
@@ -1113,6 +1409,7 @@ func (analyzer *Analyzer) registerCommonHandlers(includePositions bool) {
 		}
 
 		analyzer.bombPlantPosition = event.Player.Position()
+		analyzer.isBombPlanted = true
 
 		bombPlanted := newBombPlanted(analyzer, event)
 		match.BombsPlanted = append(match.BombsPlanted, bombPlanted)
@@ -1184,23 +1481,23 @@ func (analyzer *Analyzer) registerCommonHandlers(includePositions bool) {
 
 		projectile := event.Projectile
 		if projectile == nil {
-			fmt.Println("Projectile nil in grenade projectile throw event")
+			analyzer.warn(constants.WarningKindMissingEntity, "Projectile nil in grenade projectile throw event")
 			return
 		}
 
 		thrower := projectile.Thrower
 		if thrower == nil {
-			fmt.Println("Thrower nil in grenade projectile throw event, falling back to owner")
+			analyzer.warn(constants.WarningKindMissingEntity, "Thrower nil in grenade projectile throw event, falling back to owner")
 			thrower = projectile.WeaponInstance.Owner
 			if thrower == nil {
-				fmt.Println("Owner nil in grenade projectile throw event")
+				analyzer.warn(constants.WarningKindMissingEntity, "Owner nil in grenade projectile throw event")
 				return
 			}
 		}
 
 		lastGrenadeShotExist := analyzer.lastGrenadeThrownByPlayer[thrower.SteamID64]
 		if lastGrenadeShotExist == nil {
-			fmt.Println("A projectile throw event occurred whereas its weapon fired event didn't occurred.")
+			analyzer.warn(constants.WarningKindMissingEvent, "A projectile throw event occurred whereas its weapon fired event didn't occurred")
 		} else {
 			lastGrenadeShotExist.ProjectileID = projectile.UniqueID()
 			delete(analyzer.lastGrenadeThrownByPlayer, thrower.SteamID64)
@@ -1617,7 +1914,7 @@ func (analyzer *Analyzer) registerCommonHandlers(includePositions bool) {
 						}
 
 						// TODO notImplemented Find a demo with a VAC live ban to get the correct value
-						fmt.Println("Match aborted with reason", reason)
+						analyzer.warn(constants.WarningKindMatchAborted, "Match aborted with reason %d", reason)
 						analyzer.match.HasVacLiveBan = true
 					})
 				} else {
@@ -1653,6 +1950,8 @@ func (analyzer *Analyzer) registerCommonHandlers(includePositions bool) {
 			if terroristTimeoutRemainingProperty != nil {
 				terroristTimeoutRemainingProperty.OnUpdate(onTimeoutUpdate)
 			}
+
+			analyzer.registerTimeoutHandlers(entity)
 		})
 	})
 }
@@ -1715,4 +2014,14 @@ func defaultPostProcess(analyzer *Analyzer) {
 
 	generateAwpHoldDeaths(match)
 	markHeuristicWallbangDamages(match)
+	generateMovementEvents(match)
+	attachTimeoutChatRequests(match)
+
+	roundCount := len(match.Rounds)
+	if roundCount > 0 {
+		endReason := match.Rounds[roundCount-1].EndReason
+		if endReason == events.RoundEndReasonTerroristsSurrender || endReason == events.RoundEndReasonCTSurrender {
+			match.EndedBySurrender = true
+		}
+	}
 }
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/akiver/cs-demo-analyzer/pkg/api/constants"
+	"gopkg.in/yaml.v3"
+)
+
+// Thresholds used by the heuristics of the analysis, the zero value is not valid, use DefaultAnalyzerConfig.
+type AnalyzerConfig struct {
+	// Maximum number of seconds between a teammate death and a possible revenge kill to be considered as a trade kill.
+	TradeKillDelaySeconds float64 `json:"tradeKillDelaySeconds" yaml:"tradeKillDelaySeconds"`
+	// Number of seconds after the end of the freeze time at which players equipment value is computed.
+	EquipmentValueDelaySeconds float64 `json:"equipmentValueDelaySeconds" yaml:"equipmentValueDelaySeconds"`
+	// Seconds after the death during which an attack of the AWP victim means it was reacting.
+	AwpHoldReactionWindowSeconds float64 `json:"awpHoldReactionWindowSeconds" yaml:"awpHoldReactionWindowSeconds"`
+	// Seconds around the death to find an AWP shot of the victim.
+	AwpHoldEmptyShotWindowSeconds float64 `json:"awpHoldEmptyShotWindowSeconds" yaml:"awpHoldEmptyShotWindowSeconds"`
+	// Maximum angle in degrees between the AWP victim view and the killer to consider that it was facing the killer.
+	AwpHoldFacingAngleThreshold float64 `json:"awpHoldFacingAngleThreshold" yaml:"awpHoldFacingAngleThreshold"`
+	// Maximum number of frames between a shot and a damage to attribute the damage to the shot (first shots, wallbangs).
+	DamageAttributionMaxShotFrameDistance int `json:"damageAttributionMaxShotFrameDistance" yaml:"damageAttributionMaxShotFrameDistance"`
+	// Maximum number of frames between a damage and the victim position used by the wallbang heuristic.
+	WallbangMaxVictimPositionFrameDelta int `json:"wallbangMaxVictimPositionFrameDelta" yaml:"wallbangMaxVictimPositionFrameDelta"`
+	// Maximum number of frames between a headshot and the victim position to use its helmet state.
+	WallbangHelmetSnapshotFrameWindow int `json:"wallbangHelmetSnapshotFrameWindow" yaml:"wallbangHelmetSnapshotFrameWindow"`
+	// Minimum difference between the expected and the observed damage to suspect a wallbang.
+	WallbangMinDelta float64 `json:"wallbangMinDelta" yaml:"wallbangMinDelta"`
+	// Minimum ratio of the expected damage lost to suspect a wallbang.
+	WallbangMinLossRatio     float64 `json:"wallbangMinLossRatio" yaml:"wallbangMinLossRatio"`
+	WallbangIncludeHeadshots bool    `json:"wallbangIncludeHeadshots" yaml:"wallbangIncludeHeadshots"`
+	// Utilities thrown below this 2D speed (and not standing still) are "step" throws.
+	ThrowerStepMaxSpeed float64 `json:"throwerStepMaxSpeed" yaml:"throwerStepMaxSpeed"`
+	// Utilities thrown below this 2D speed are "walk" throws, "run" throws above.
+	ThrowerWalkMaxSpeed float64 `json:"throwerWalkMaxSpeed" yaml:"throwerWalkMaxSpeed"`
+}
+
+func DefaultAnalyzerConfig() AnalyzerConfig {
+	return AnalyzerConfig{
+		TradeKillDelaySeconds:                 tradeKillDelaySeconds,
+		EquipmentValueDelaySeconds:            equipmentValueDelaySeconds,
+		AwpHoldReactionWindowSeconds:          awpHoldReactionWindowSeconds,
+		AwpHoldEmptyShotWindowSeconds:         awpHoldEmptyShotWindowSeconds,
+		AwpHoldFacingAngleThreshold:           awpHoldFacingAngleThreshold,
+		DamageAttributionMaxShotFrameDistance: constants.HeuristicDamageAttributionMaxShotFrameDistance,
+		WallbangMaxVictimPositionFrameDelta:   constants.HeuristicWallbangMaxVictimPositionFrameDelta,
+		WallbangHelmetSnapshotFrameWindow:     constants.HeuristicWallbangHelmetSnapshotFrameWindow,
+		WallbangMinDelta:                      constants.HeuristicWallbangMinDelta,
+		WallbangMinLossRatio:                  constants.HeuristicWallbangMinLossRatio,
+		WallbangIncludeHeadshots:              constants.HeuristicWallbangIncludeHeadshots,
+		ThrowerStepMaxSpeed:                   throwerStepMaxSpeed,
+		ThrowerWalkMaxSpeed:                   throwerWalkMaxSpeed,
+	}
+}
+
+type analyzerConfigValue struct {
+	name  string
+	value float64
+}
+
+func (config AnalyzerConfig) Validate() error {
+	var errs []string
+	positiveValues := []analyzerConfigValue{
+		{"tradeKillDelaySeconds", config.TradeKillDelaySeconds},
+		{"awpHoldReactionWindowSeconds", config.AwpHoldReactionWindowSeconds},
+		{"awpHoldEmptyShotWindowSeconds", config.AwpHoldEmptyShotWindowSeconds},
+		{"awpHoldFacingAngleThreshold", config.AwpHoldFacingAngleThreshold},
+		{"damageAttributionMaxShotFrameDistance", float64(config.DamageAttributionMaxShotFrameDistance)},
+		{"wallbangMaxVictimPositionFrameDelta", float64(config.WallbangMaxVictimPositionFrameDelta)},
+		{"throwerStepMaxSpeed", config.ThrowerStepMaxSpeed},
+	}
+	for _, value := range positiveValues {
+		if value.value <= 0 {
+			errs = append(errs, value.name+" must be greater than 0")
+		}
+	}
+
+	nonNegativeValues := []analyzerConfigValue{
+		{"equipmentValueDelaySeconds", config.EquipmentValueDelaySeconds},
+		{"wallbangHelmetSnapshotFrameWindow", float64(config.WallbangHelmetSnapshotFrameWindow)},
+		{"wallbangMinDelta", config.WallbangMinDelta},
+		{"wallbangMinLossRatio", config.WallbangMinLossRatio},
+	}
+	for _, value := range nonNegativeValues {
+		if value.value < 0 {
+			errs = append(errs, value.name+" must not be negative")
+		}
+	}
+
+	if config.ThrowerWalkMaxSpeed <= config.ThrowerStepMaxSpeed {
+		errs = append(errs, "throwerWalkMaxSpeed must be greater than throwerStepMaxSpeed")
+	}
+
+	if len(errs) > 0 {
+		return newError(ErrorCodeInvalidArgument, "invalid analyzer config: "+strings.Join(errs, ", "), nil)
+	}
+
+	return nil
+}
+
+// Loads a YAML or JSON (.json extension) config file, missing values are set to their default value.
+func LoadAnalyzerConfig(configPath string) (AnalyzerConfig, error) {
+	config := DefaultAnalyzerConfig()
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return config, newError(ErrorCodeInvalidArgument, fmt.Sprintf("unable to read config file %q", configPath), err)
+	}
+
+	if strings.EqualFold(filepath.Ext(configPath), ".json") {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&config)
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&config)
+		// Empty file.
+		if errors.Is(err, io.EOF) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return config, newError(ErrorCodeInvalidArgument, fmt.Sprintf("invalid config file %q", configPath), err)
+	}
+
+	return config, config.Validate()
+}
+
+// Written next to the exported files for reproducibility, the file can be given back with the -config flag.
+func writeAnalyzerConfigFile(match *Match, filePath string) error {
+	data, err := json.MarshalIndent(match.config(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filePath, data, os.ModePerm)
+}
+
+// Returns the config used during the analysis, the default config if the match has not been analyzed.
+func (match *Match) config() AnalyzerConfig {
+	if match.AnalyzerConfig == nil {
+		return DefaultAnalyzerConfig()
+	}
+
+	return *match.AnalyzerConfig
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeConfigFile(t *testing.T, name string, content string) string {
+	t.Helper()
+	configPath := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(configPath, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return configPath
+}
+
+func TestLoadAnalyzerConfig_YAMLOverridesDefaults(t *testing.T) {
+	configPath := writeConfigFile(t, "heuristics.yaml", "tradeKillDelaySeconds: 3.5\nwallbangIncludeHeadshots: false\n")
+
+	config, err := LoadAnalyzerConfig(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := DefaultAnalyzerConfig()
+	expected.TradeKillDelaySeconds = 3.5
+	expected.WallbangIncludeHeadshots = false
+	if config != expected {
+		t.Errorf("expected %+v got %+v", expected, config)
+	}
+}
+
+func TestLoadAnalyzerConfig_JSON(t *testing.T) {
+	configPath := writeConfigFile(t, "heuristics.json", `{"damageAttributionMaxShotFrameDistance": 32}`)
+
+	config, err := LoadAnalyzerConfig(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.DamageAttributionMaxShotFrameDistance != 32 {
+		t.Errorf("expected 32 got %d", config.DamageAttributionMaxShotFrameDistance)
+	}
+}
+
+func TestLoadAnalyzerConfig_EmptyFile(t *testing.T) {
+	config, err := LoadAnalyzerConfig(writeConfigFile(t, "heuristics.yaml", ""))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config != DefaultAnalyzerConfig() {
+		t.Errorf("expected the default config got %+v", config)
+	}
+}
+
+func TestLoadAnalyzerConfig_Invalid(t *testing.T) {
+	samples := map[string]string{
+		"unknown.yaml":  "tradeKillDelay: 3\n",
+		"unknown.json":  `{"tradeKillDelay": 3}`,
+		"negative.yaml": "tradeKillDelaySeconds: -1\n",
+		"speeds.yaml":   "throwerStepMaxSpeed: 200\n",
+	}
+
+	for name, content := range samples {
+		_, err := LoadAnalyzerConfig(writeConfigFile(t, name, content))
+		if !errors.Is(err, ErrInvalidArgument) {
+			t.Errorf("expected ErrInvalidArgument for %s got %v", name, err)
+		}
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
 )
 
+// awpHold* values are the defaults of AnalyzerConfig.
 const (
 	awpHoldReactionWindowSeconds    = 1.0
 	awpHoldEmptyShotWindowSeconds   = 0.5
@@ -80,10 +81,11 @@ func generateAwpHoldDeaths(match *Match) {
 	if frameRate <= 0 {
 		frameRate = tickRate
 	}
-	reactionWindowTicks := max(1, int(tickRate*awpHoldReactionWindowSeconds))
-	reactionWindowFrames := max(1, int(frameRate*awpHoldReactionWindowSeconds))
-	emptyShotWindowTicks := max(1, int(tickRate*awpHoldEmptyShotWindowSeconds))
-	emptyShotWindowFrames := max(1, int(frameRate*awpHoldEmptyShotWindowSeconds))
+	config := match.config()
+	reactionWindowTicks := max(1, int(tickRate*config.AwpHoldReactionWindowSeconds))
+	reactionWindowFrames := max(1, int(frameRate*config.AwpHoldReactionWindowSeconds))
+	emptyShotWindowTicks := max(1, int(tickRate*config.AwpHoldEmptyShotWindowSeconds))
+	emptyShotWindowFrames := max(1, int(frameRate*config.AwpHoldEmptyShotWindowSeconds))
 
 	victimAwpShotsByRoundPlayer := buildVictimAwpShotsByRoundPlayer(match)
 	victimButtonsByRoundPlayer := buildPlayerButtonsByRoundPlayer(match)
@@ -139,7 +141,7 @@ func generateAwpHoldDeaths(match *Match) {
 		if killerSnapshot.position != nil && killerSnapshot.hasVelocity {
 			killerVelocity = killerSnapshot.velocity
 		}
-		isVictimFacingKiller, victimFacingKillerAngleDeg := victimFacingKiller(victimYaw, victimPosition, killerPosition)
+		isVictimFacingKiller, victimFacingKillerAngleDeg := victimFacingKiller(victimYaw, victimPosition, killerPosition, config.AwpHoldFacingAngleThreshold)
 
 		victimSpeed2D := math.Sqrt(victimVelocity.X*victimVelocity.X + victimVelocity.Y*victimVelocity.Y)
 		killerSpeed2D := math.Sqrt(killerVelocity.X*killerVelocity.X + killerVelocity.Y*killerVelocity.Y)
@@ -386,7 +388,7 @@ func nearestPlayerSnapshotAtOrBefore(tick int, positions []*PlayerPosition, tick
 	return playerPositionSnapshot{position: position, velocity: velocity, hasVelocity: hasVelocity}
 }
 
-func victimFacingKiller(victimYaw float32, victimPosition r3.Vector, killerPosition r3.Vector) (bool, float64) {
+func victimFacingKiller(victimYaw float32, victimPosition r3.Vector, killerPosition r3.Vector, angleThreshold float64) (bool, float64) {
 	directionToKiller := r3.Vector{X: killerPosition.X - victimPosition.X, Y: killerPosition.Y - victimPosition.Y}
 	distance := math.Sqrt(directionToKiller.X*directionToKiller.X + directionToKiller.Y*directionToKiller.Y)
 	if distance == 0 {
@@ -407,7 +409,7 @@ func victimFacingKiller(victimYaw float32, victimPosition r3.Vector, killerPosit
 	}
 
 	angleDeg := math.Acos(dot) * (180.0 / math.Pi)
-	return angleDeg <= awpHoldFacingAngleThreshold, angleDeg
+	return angleDeg <= angleThreshold, angleDeg
 }
 
 func classifyMovementBucket(speed2D float64) string {
@@ -0,0 +1,192 @@
+package api
+
+import (
+	"github.com/akiver/cs-demo-analyzer/pkg/api/constants"
+	"github.com/golang/geo/r3"
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
+)
+
+type BombEvent struct {
+	Frame                    int                     `json:"frame"`
+	Tick                     int                     `json:"tick"`
+	RoundNumber              int                     `json:"roundNumber"`
+	Type                     constants.BombEventType `json:"type"`
+	PlayerName               string                  `json:"playerName"` // The bomb carrier, or the player who dropped the bomb
+	PlayerSteamID64          uint64                  `json:"playerSteamId"`
+	IsPlayerControllingBot   bool                    `json:"isPlayerControllingBot"`
+	PreviousCarrierName      string                  `json:"previousCarrierName"` // Pickups and carrier changes only
+	PreviousCarrierSteamID64 uint64                  `json:"previousCarrierSteamId"`
+	GroundTimeSeconds        float64                 `json:"groundTimeSeconds"` // Pickups and round ends, seconds the bomb spent on the ground
+	KillerName               string                  `json:"killerName"`        // Kills only
+	KillerSteamID64          uint64                  `json:"killerSteamId"`
+	VictimName               string                  `json:"victimName"`
+	VictimSteamID64          uint64                  `json:"victimSteamId"`
+	X                        float64                 `json:"x"`
+	Y                        float64                 `json:"y"`
+	Z                        float64                 `json:"z"`
+}
+
+// State of the bomb read from the parser at the end of a frame.
+type bombFrameState struct {
+	frame          int
+	tick           int
+	tickTime       float64 // Seconds
+	carrier        *common.Player
+	isCarrierAlive bool
+	bombPosition   r3.Vector
+}
+
+func (analyzer *Analyzer) bombFrameState() bombFrameState {
+	parser := analyzer.parser
+	state := bombFrameState{
+		frame:    parser.CurrentFrame(),
+		tick:     analyzer.currentTick(),
+		tickTime: parser.TickTime().Seconds(),
+	}
+	if bomb := parser.GameState().Bomb(); bomb != nil {
+		state.carrier = bomb.Carrier
+		state.bombPosition = bomb.Position()
+	}
+	if state.carrier != nil {
+		state.isCarrierAlive = state.carrier.IsAlive()
+	}
+
+	return state
+}
+
+func newBombEvent(analyzer *Analyzer, state bombFrameState, eventType constants.BombEventType, player *common.Player) *BombEvent {
+	return &BombEvent{
+		Frame:                  state.frame,
+		Tick:                   state.tick,
+		RoundNumber:            analyzer.currentRound.Number,
+		Type:                   eventType,
+		PlayerName:             player.Name,
+		PlayerSteamID64:        player.SteamID64,
+		IsPlayerControllingBot: player.IsControllingBot(),
+		X:                      player.Position().X,
+		Y:                      player.Position().Y,
+		Z:                      player.Position().Z,
+	}
+}
+
+// Must be called from the FrameDone handler.
+func (analyzer *Analyzer) updateBombCarrier() {
+	state := analyzer.bombFrameState()
+	analyzer.updateBombCarryTime(state)
+	analyzer.applyBombCarrier(state)
+}
+
+// The time elapsed since the previous frame is credited to the carrier of the previous frame.
+// It's not done with the player movements because they ignore bots.
+func (analyzer *Analyzer) updateBombCarryTime(state bombFrameState) {
+	previousTick := analyzer.lastBombCarryTick
+	analyzer.lastBombCarryTick = state.tick
+
+	carrier := analyzer.bombCarrier
+	round := analyzer.currentRound
+	if carrier == nil || carrier != state.carrier || !state.isCarrierAlive || round.FreezeTimeEndTick == -1 || round.EndTick > 0 {
+		return
+	}
+	// Movements are keyed by SteamID, bots would share the same row.
+	if carrier.IsBot || carrier.SteamID64 == 0 {
+		return
+	}
+	if previousTick == -1 || state.tick <= previousTick {
+		return
+	}
+
+	analyzer.playerRoundMovement(carrier).BombCarryTime += float64(state.tick-previousTick) * state.tickTime
+}
+
+// Detect bomb pickups and drops by comparing the current bomb carrier with the one of the previous frame.
+// It's done from the game state rather than from bomb_pickup/bomb_dropped events because these events are not reliable
+// around round starts and when the bomb is directly given to another player.
+func (analyzer *Analyzer) applyBombCarrier(state bombFrameState) {
+	carrier := state.carrier
+	previousCarrier := analyzer.bombCarrier
+	if carrier == previousCarrier {
+		return
+	}
+
+	match := analyzer.match
+	if previousCarrier != nil {
+		analyzer.bombCarrier = nil
+		// The bomb is no longer carried when it has been planted, it's not a drop.
+		if !analyzer.isBombPlanted {
+			match.BombEvents = append(match.BombEvents, newBombEvent(analyzer, state, constants.BombEventTypeDrop, previousCarrier))
+			analyzer.bombDroppedTick = state.tick
+		}
+	}
+
+	if carrier == nil {
+		return
+	}
+
+	pickup := newBombEvent(analyzer, state, constants.BombEventTypePickup, carrier)
+	lastCarrier := analyzer.lastBombCarrier
+	if lastCarrier != nil {
+		pickup.PreviousCarrierName = lastCarrier.Name
+		pickup.PreviousCarrierSteamID64 = lastCarrier.SteamID64
+	}
+	if analyzer.bombDroppedTick != -1 {
+		pickup.GroundTimeSeconds = float64(state.tick-analyzer.bombDroppedTick) * state.tickTime
+	}
+	match.BombEvents = append(match.BombEvents, pickup)
+
+	if lastCarrier != nil && lastCarrier != carrier {
+		carrierChange := newBombEvent(analyzer, state, constants.BombEventTypeCarrierChange, carrier)
+		carrierChange.PreviousCarrierName = lastCarrier.Name
+		carrierChange.PreviousCarrierSteamID64 = lastCarrier.SteamID64
+		match.BombEvents = append(match.BombEvents, carrierChange)
+	}
+
+	analyzer.bombCarrier = carrier
+	analyzer.lastBombCarrier = carrier
+	analyzer.bombDroppedTick = -1
+}
+
+// Must be called when the round ends, the time the bomb spent on the ground would be lost if it's not picked up again.
+// The player fields are the last carrier and the position is the bomb one.
+func (analyzer *Analyzer) registerBombOnGroundAtRoundEnd(state bombFrameState) {
+	lastCarrier := analyzer.lastBombCarrier
+	if analyzer.bombDroppedTick == -1 || analyzer.isBombPlanted || lastCarrier == nil {
+		return
+	}
+
+	event := newBombEvent(analyzer, state, constants.BombEventTypeOnGroundAtRoundEnd, lastCarrier)
+	event.GroundTimeSeconds = float64(state.tick-analyzer.bombDroppedTick) * state.tickTime
+	event.X = state.bombPosition.X
+	event.Y = state.bombPosition.Y
+	event.Z = state.bombPosition.Z
+	analyzer.match.BombEvents = append(analyzer.match.BombEvents, event)
+	analyzer.bombDroppedTick = -1
+}
+
+// Must be called from the Kill handler, the bomb carrier is the one detected during the previous frame so it's still
+// available when the carrier is the victim.
+func (analyzer *Analyzer) registerBombCarrierKill(kill *Kill, state bombFrameState) {
+	carrier := analyzer.bombCarrier
+	if carrier == nil || kill == nil {
+		return
+	}
+
+	eventType := constants.BombEventTypeKill
+	if kill.VictimSteamID64 == carrier.SteamID64 && kill.VictimName == carrier.Name {
+		eventType = constants.BombEventTypeCarrierKilled
+	}
+
+	event := newBombEvent(analyzer, state, eventType, carrier)
+	event.KillerName = kill.KillerName
+	event.KillerSteamID64 = kill.KillerSteamID64
+	event.VictimName = kill.VictimName
+	event.VictimSteamID64 = kill.VictimSteamID64
+	analyzer.match.BombEvents = append(analyzer.match.BombEvents, event)
+}
+
+func (analyzer *Analyzer) resetBombCarrier() {
+	analyzer.bombCarrier = nil
+	analyzer.lastBombCarrier = nil
+	analyzer.bombDroppedTick = -1
+	analyzer.lastBombCarryTick = -1
+	analyzer.isBombPlanted = false
+}
@@ -0,0 +1,189 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/akiver/cs-demo-analyzer/pkg/api/constants"
+	"github.com/golang/geo/r3"
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
+)
+
+func newBombEventTestAnalyzer() *Analyzer {
+	counterTerrorists := common.TeamCounterTerrorists
+	terrorists := common.TeamTerrorists
+	return &Analyzer{
+		match: &Match{
+			TeamA:                     &Team{Name: "Team A", CurrentSide: &counterTerrorists},
+			TeamB:                     &Team{Name: "Team B", CurrentSide: &terrorists},
+			BombEvents:                []*BombEvent{},
+			playerRoundMovementsByKey: make(map[roundPlayerKey]*PlayerRoundMovement),
+		},
+		currentRound:      &Round{Number: 1, FreezeTimeEndTick: 0},
+		bombDroppedTick:   -1,
+		lastBombCarryTick: -1,
+	}
+}
+
+// Simulates the FrameDone handler with a tick rate of 64.
+func applyBombTestFrame(analyzer *Analyzer, tick int, carrier *common.Player) {
+	state := bombFrameState{
+		frame:          tick,
+		tick:           tick,
+		tickTime:       1.0 / 64,
+		carrier:        carrier,
+		isCarrierAlive: carrier != nil,
+		bombPosition:   r3.Vector{X: 100, Y: 200, Z: 10},
+	}
+	analyzer.updateBombCarryTime(state)
+	analyzer.applyBombCarrier(state)
+}
+
+func bombEventTypes(events []*BombEvent) []constants.BombEventType {
+	types := []constants.BombEventType{}
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+
+	return types
+}
+
+func TestBombCarrier_PickupDropAndCarrierChange(t *testing.T) {
+	analyzer := newBombEventTestAnalyzer()
+	foo := &common.Player{Name: "foo", SteamID64: 1, Team: common.TeamTerrorists}
+	bar := &common.Player{Name: "bar", SteamID64: 2, Team: common.TeamTerrorists}
+
+	applyBombTestFrame(analyzer, 10, foo)
+	applyBombTestFrame(analyzer, 20, nil)
+	applyBombTestFrame(analyzer, 20+64*2, bar)
+
+	events := analyzer.match.BombEvents
+	expectedTypes := []constants.BombEventType{
+		constants.BombEventTypePickup,
+		constants.BombEventTypeDrop,
+		constants.BombEventTypePickup,
+		constants.BombEventTypeCarrierChange,
+	}
+	types := bombEventTypes(events)
+	if len(types) != len(expectedTypes) {
+		t.Fatalf("expected events %v got %v", expectedTypes, types)
+	}
+	for index, expectedType := range expectedTypes {
+		if types[index] != expectedType {
+			t.Fatalf("expected events %v got %v", expectedTypes, types)
+		}
+	}
+
+	if events[1].PlayerName != "foo" || events[1].Tick != 20 {
+		t.Errorf("expected foo to drop the bomb at tick 20 got %+v", events[1])
+	}
+	pickup := events[2]
+	if pickup.PlayerName != "bar" || pickup.PreviousCarrierSteamID64 != 1 || pickup.GroundTimeSeconds != 2 {
+		t.Errorf("expected bar to pick up the bomb of foo after 2 seconds got %+v", pickup)
+	}
+	if events[3].PlayerSteamID64 != 2 || events[3].PreviousCarrierName != "foo" {
+		t.Errorf("unexpected carrier change %+v", events[3])
+	}
+}
+
+func TestBombCarrier_PlantIsNotADrop(t *testing.T) {
+	analyzer := newBombEventTestAnalyzer()
+	foo := &common.Player{Name: "foo", SteamID64: 1, Team: common.TeamTerrorists}
+
+	applyBombTestFrame(analyzer, 10, foo)
+	analyzer.isBombPlanted = true
+	applyBombTestFrame(analyzer, 20, nil)
+	analyzer.registerBombOnGroundAtRoundEnd(bombFrameState{tick: 100, tickTime: 1.0 / 64})
+
+	if len(analyzer.match.BombEvents) != 1 || analyzer.match.BombEvents[0].Type != constants.BombEventTypePickup {
+		t.Errorf("expected only the pickup got %v", bombEventTypes(analyzer.match.BombEvents))
+	}
+}
+
+func TestBombCarrier_GroundTimeAtRoundEnd(t *testing.T) {
+	analyzer := newBombEventTestAnalyzer()
+	foo := &common.Player{Name: "foo", SteamID64: 1, Team: common.TeamTerrorists}
+
+	applyBombTestFrame(analyzer, 10, foo)
+	applyBombTestFrame(analyzer, 20, nil)
+	analyzer.registerBombOnGroundAtRoundEnd(bombFrameState{frame: 340, tick: 340, tickTime: 1.0 / 64, bombPosition: r3.Vector{X: 100, Y: 200, Z: 10}})
+
+	events := analyzer.match.BombEvents
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events got %v", bombEventTypes(events))
+	}
+	event := events[2]
+	if event.Type != constants.BombEventTypeOnGroundAtRoundEnd || event.GroundTimeSeconds != 5 || event.PlayerName != "foo" || event.X != 100 {
+		t.Errorf("expected the bomb dropped by foo to be on the ground for 5 seconds got %+v", event)
+	}
+
+	// The ground time is recorded once.
+	analyzer.registerBombOnGroundAtRoundEnd(bombFrameState{tick: 400, tickTime: 1.0 / 64})
+	if len(analyzer.match.BombEvents) != 3 {
+		t.Errorf("expected no new events got %v", bombEventTypes(analyzer.match.BombEvents))
+	}
+}
+
+func TestBombCarrier_KillsWhileCarried(t *testing.T) {
+	analyzer := newBombEventTestAnalyzer()
+	foo := &common.Player{Name: "foo", SteamID64: 1, Team: common.TeamTerrorists}
+
+	analyzer.registerBombCarrierKill(&Kill{KillerName: "foo", KillerSteamID64: 1, VictimName: "bar", VictimSteamID64: 2}, bombFrameState{tick: 5})
+	if len(analyzer.match.BombEvents) != 0 {
+		t.Fatalf("expected no events without carrier got %v", bombEventTypes(analyzer.match.BombEvents))
+	}
+
+	applyBombTestFrame(analyzer, 10, foo)
+	analyzer.registerBombCarrierKill(&Kill{KillerName: "foo", KillerSteamID64: 1, VictimName: "bar", VictimSteamID64: 2}, bombFrameState{tick: 15})
+	analyzer.registerBombCarrierKill(&Kill{KillerName: "baz", KillerSteamID64: 3, VictimName: "foo", VictimSteamID64: 1}, bombFrameState{tick: 20})
+
+	events := analyzer.match.BombEvents
+	if len(events) != 3 || events[1].Type != constants.BombEventTypeKill || events[2].Type != constants.BombEventTypeCarrierKilled {
+		t.Fatalf("expected a kill and a carrier killed events got %v", bombEventTypes(events))
+	}
+	if events[2].PlayerName != "foo" || events[2].KillerSteamID64 != 3 || events[2].Tick != 20 {
+		t.Errorf("unexpected carrier killed event %+v", events[2])
+	}
+}
+
+func TestBombCarrier_CarryTime(t *testing.T) {
+	analyzer := newBombEventTestAnalyzer()
+	foo := &common.Player{Name: "foo", SteamID64: 1, Team: common.TeamTerrorists}
+
+	// Freeze time.
+	analyzer.currentRound.FreezeTimeEndTick = -1
+	applyBombTestFrame(analyzer, 0, foo)
+	applyBombTestFrame(analyzer, 64, foo)
+	analyzer.currentRound.FreezeTimeEndTick = 64
+	applyBombTestFrame(analyzer, 128, foo)
+	applyBombTestFrame(analyzer, 160, foo)
+	// The frame of the drop and the time after it are not carry time.
+	applyBombTestFrame(analyzer, 192, nil)
+	applyBombTestFrame(analyzer, 256, nil)
+
+	movements := analyzer.match.PlayerRoundMovements
+	if len(movements) != 1 || movements[0].PlayerSteamID64 != 1 {
+		t.Fatalf("expected a movement for foo got %v", movements)
+	}
+	if movements[0].BombCarryTime != 1.5 {
+		t.Errorf("expected 1.5 seconds of carry time got %f", movements[0].BombCarryTime)
+	}
+}
+
+func TestBombCarrier_CarryTimeIgnoresBots(t *testing.T) {
+	analyzer := newBombEventTestAnalyzer()
+	joe := &common.Player{Name: "BOT Joe", IsBot: true, Team: common.TeamTerrorists}
+	jim := &common.Player{Name: "BOT Jim", IsBot: true, Team: common.TeamTerrorists}
+
+	applyBombTestFrame(analyzer, 64, joe)
+	applyBombTestFrame(analyzer, 128, joe)
+	applyBombTestFrame(analyzer, 192, jim)
+	applyBombTestFrame(analyzer, 256, jim)
+
+	// Bots have no SteamID, they would share the same movement row.
+	if len(analyzer.match.PlayerRoundMovements) != 0 {
+		t.Errorf("expected no movements for bots got %v", analyzer.match.PlayerRoundMovements)
+	}
+	if len(analyzer.match.BombEvents) != 4 {
+		t.Errorf("expected the bomb events of the bots to be recorded got %v", bombEventTypes(analyzer.match.BombEvents))
+	}
+}
@@ -0,0 +1,17 @@
+package constants
+
+type BombEventType string
+
+func (eventType BombEventType) String() string {
+	return string(eventType)
+}
+
+const (
+	BombEventTypePickup        BombEventType = "pickup"
+	BombEventTypeDrop          BombEventType = "drop"
+	BombEventTypeCarrierChange BombEventType = "carrier_change"
+	BombEventTypeKill          BombEventType = "kill"
+	BombEventTypeCarrierKilled BombEventType = "carrier_killed"
+	// The bomb was still on the ground when the round ended.
+	BombEventTypeOnGroundAtRoundEnd BombEventType = "on_ground_at_round_end"
+)
@@ -0,0 +1,14 @@
+package constants
+
+type LoadoutItemType string
+
+func (itemType LoadoutItemType) String() string {
+	return string(itemType)
+}
+
+const (
+	LoadoutItemTypeWeapon   LoadoutItemType = "weapon"
+	LoadoutItemTypeGloves   LoadoutItemType = "gloves"
+	LoadoutItemTypeAgent    LoadoutItemType = "agent"
+	LoadoutItemTypeMusicKit LoadoutItemType = "music_kit"
+)
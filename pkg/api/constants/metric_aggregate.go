@@ -0,0 +1,23 @@
+package constants
+
+type MetricAggregate string
+
+func (aggregate MetricAggregate) String() string {
+	return string(aggregate)
+}
+
+const (
+	MetricAggregateCount MetricAggregate = "count"
+	MetricAggregateSum   MetricAggregate = "sum"
+	MetricAggregateAvg   MetricAggregate = "avg"
+	MetricAggregateMin   MetricAggregate = "min"
+	MetricAggregateMax   MetricAggregate = "max"
+)
+
+var MetricAggregates = []MetricAggregate{
+	MetricAggregateCount,
+	MetricAggregateSum,
+	MetricAggregateAvg,
+	MetricAggregateMin,
+	MetricAggregateMax,
+}
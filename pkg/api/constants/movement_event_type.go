@@ -0,0 +1,15 @@
+package constants
+
+type MovementEventType string
+
+func (eventType MovementEventType) String() string {
+	return string(eventType)
+}
+
+const (
+	MovementEventTypeBhopChain  MovementEventType = "bhop_chain"
+	MovementEventTypeJigglePeek MovementEventType = "jiggle_peek"
+	MovementEventTypeCrouchSpam MovementEventType = "crouch_spam"
+	MovementEventTypeJumpShot   MovementEventType = "jump_shot"
+	MovementEventTypeWideSwing  MovementEventType = "wide_swing"
+)
@@ -0,0 +1,15 @@
+package constants
+
+type PlayerRole string
+
+func (role PlayerRole) String() string {
+	return string(role)
+}
+
+const (
+	PlayerRoleEntry   PlayerRole = "entry"
+	PlayerRoleLurker  PlayerRole = "lurker"
+	PlayerRoleAwper   PlayerRole = "awper"
+	PlayerRoleAnchor  PlayerRole = "anchor"
+	PlayerRoleSupport PlayerRole = "support"
+)
@@ -0,0 +1,15 @@
+package constants
+
+type PlayerSessionEventType string
+
+func (eventType PlayerSessionEventType) String() string {
+	return string(eventType)
+}
+
+const (
+	PlayerSessionEventTypeConnect     PlayerSessionEventType = "connect"
+	PlayerSessionEventTypeDisconnect  PlayerSessionEventType = "disconnect"
+	PlayerSessionEventTypeReconnect   PlayerSessionEventType = "reconnect"
+	PlayerSessionEventTypeTeamChange  PlayerSessionEventType = "team_change"
+	PlayerSessionEventTypeBotTakeover PlayerSessionEventType = "bot_takeover"
+)
@@ -0,0 +1,25 @@
+package constants
+
+type TimeoutType string
+
+func (timeoutType TimeoutType) String() string {
+	return string(timeoutType)
+}
+
+const (
+	TimeoutTypeTactical  TimeoutType = "tactical"
+	TimeoutTypeTechnical TimeoutType = "technical"
+	// Match paused by an admin or a server plugin, it's also used by MatchZy during backup restores.
+	TimeoutTypePause TimeoutType = "pause"
+)
+
+type TimeoutSource string
+
+func (source TimeoutSource) String() string {
+	return string(source)
+}
+
+const (
+	TimeoutSourceGameRules TimeoutSource = "game_rules"
+	TimeoutSourceChat      TimeoutSource = "chat"
+)
@@ -0,0 +1,22 @@
+package constants
+
+type ValidationCheck string
+
+func (check ValidationCheck) String() string {
+	return string(check)
+}
+
+const (
+	// Teams score equals the number of rounds they won and the score of the last round.
+	ValidationCheckTeamScores ValidationCheck = "team_scores"
+	// Rounds are numbered from 1 without gaps.
+	ValidationCheckRoundNumbers ValidationCheck = "round_numbers"
+	// Each round starts before the end of its freeze time which is before the round end.
+	ValidationCheckRoundTicks ValidationCheck = "round_ticks"
+	// There are at most 10 kills per round, plus suicides.
+	ValidationCheckRoundKills ValidationCheck = "round_kills"
+	// Each player who played a round has an economy for this round.
+	ValidationCheckPlayerEconomies ValidationCheck = "player_economies"
+	// The number of regulation rounds and the scores are consistent with the max rounds.
+	ValidationCheckMaxRounds ValidationCheck = "max_rounds"
+)
@@ -0,0 +1,61 @@
+package constants
+
+type VoteType string
+
+func (voteType VoteType) String() string {
+	return string(voteType)
+}
+
+const (
+	VoteTypeKick             VoteType = "kick"
+	VoteTypeChangeLevel      VoteType = "change_level"
+	VoteTypeNextLevel        VoteType = "next_level"
+	VoteTypeSwapTeams        VoteType = "swap_teams"
+	VoteTypeScrambleTeams    VoteType = "scramble_teams"
+	VoteTypeRestartGame      VoteType = "restart_game"
+	VoteTypeSurrender        VoteType = "surrender"
+	VoteTypeRematch          VoteType = "rematch"
+	VoteTypeContinue         VoteType = "continue"
+	VoteTypePauseMatch       VoteType = "pause_match"
+	VoteTypeUnpauseMatch     VoteType = "unpause_match"
+	VoteTypeLoadBackup       VoteType = "load_backup"
+	VoteTypeEndWarmup        VoteType = "end_warmup"
+	VoteTypeStartTimeout     VoteType = "start_timeout"
+	VoteTypeEndTimeout       VoteType = "end_timeout"
+	VoteTypeReadyForMatch    VoteType = "ready_for_match"
+	VoteTypeNotReadyForMatch VoteType = "not_ready_for_match"
+	VoteTypeUnknown          VoteType = "unknown"
+)
+
+// Values of the game vote_issue_t enum sent with the VoteStart and VotePass user messages.
+var VoteTypeByIssue = map[int32]VoteType{
+	0:  VoteTypeKick,
+	1:  VoteTypeChangeLevel,
+	2:  VoteTypeNextLevel,
+	3:  VoteTypeSwapTeams,
+	4:  VoteTypeScrambleTeams,
+	5:  VoteTypeRestartGame,
+	6:  VoteTypeSurrender,
+	7:  VoteTypeRematch,
+	8:  VoteTypeContinue,
+	9:  VoteTypePauseMatch,
+	10: VoteTypeUnpauseMatch,
+	11: VoteTypeLoadBackup,
+	12: VoteTypeEndWarmup,
+	13: VoteTypeStartTimeout,
+	14: VoteTypeEndTimeout,
+	15: VoteTypeReadyForMatch,
+	16: VoteTypeNotReadyForMatch,
+}
+
+type VoteOutcome string
+
+func (outcome VoteOutcome) String() string {
+	return string(outcome)
+}
+
+const (
+	VoteOutcomePending VoteOutcome = "pending" // The demo ended before the end of the vote
+	VoteOutcomePassed  VoteOutcome = "passed"
+	VoteOutcomeFailed  VoteOutcome = "failed"
+)
@@ -0,0 +1,24 @@
+package constants
+
+type WarningKind string
+
+func (kind WarningKind) String() string {
+	return string(kind)
+}
+
+const (
+	// An event occurred without an entity the analyzer relies on (thrower, grenade, weapon, victim...), it has been ignored.
+	WarningKindMissingEntity WarningKind = "missing_entity"
+	// A grenade projectile has been thrown without the matching weapon fire event.
+	WarningKindMissingEvent WarningKind = "missing_event"
+	// The weapon is not in the accurate speed constants, the "running" columns are false.
+	WarningKindUnknownWeapon WarningKind = "unknown_weapon"
+	// The game type and game mode combination is unknown, the game mode is considered competitive.
+	WarningKindUnknownGameMode WarningKind = "unknown_game_mode"
+	// Warning raised by the demo parser.
+	WarningKindParser WarningKind = "parser"
+	// The .info file next to the demo can't be read.
+	WarningKindInvalidInfoFile WarningKind = "invalid_info_file"
+	// The match has been aborted, usually because of a VAC live ban.
+	WarningKindMatchAborted WarningKind = "match_aborted"
+)
@@ -0,0 +1,26 @@
+package api
+
+// Crosshair settings decoded from a player crosshair share code, values are the ones of the cl_crosshair* convars.
+type CrosshairSettings struct {
+	Style                      int     `json:"style"` // cl_crosshairstyle
+	Size                       float64 `json:"size"`
+	Gap                        float64 `json:"gap"`
+	Thickness                  float64 `json:"thickness"`
+	Color                      int     `json:"color"` // cl_crosshaircolor, 5 means custom RGB values
+	Red                        uint8   `json:"red"`
+	Green                      uint8   `json:"green"`
+	Blue                       uint8   `json:"blue"`
+	Alpha                      uint8   `json:"alpha"`
+	IsAlphaEnabled             bool    `json:"isAlphaEnabled"`
+	IsOutlineEnabled           bool    `json:"isOutlineEnabled"`
+	OutlineThickness           float64 `json:"outlineThickness"`
+	IsCenterDotEnabled         bool    `json:"isCenterDotEnabled"`
+	IsTStyleEnabled            bool    `json:"isTStyleEnabled"`
+	IsFollowRecoilEnabled      bool    `json:"isFollowRecoilEnabled"`
+	IsDeployedWeaponGapEnabled bool    `json:"isDeployedWeaponGapEnabled"`
+	FixedGap                   float64 `json:"fixedGap"`
+	SplitDistance              int     `json:"splitDistance"`
+	InnerSplitAlpha            float64 `json:"innerSplitAlpha"`
+	OuterSplitAlpha            float64 `json:"outerSplitAlpha"`
+	SplitSizeRatio             float64 `json:"splitSizeRatio"`
+}
@@ -0,0 +1,27 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/akiver/cs-demo-analyzer/internal/demo"
+)
+
+func TestPlayerCrosshairSettings(t *testing.T) {
+	shareCode := demo.EncodeCrosshairShareCode(demo.CrosshairSettings{Style: 4, Size: 2, Gap: -3, Thickness: 1, Color: 1, IsCenterDotEnabled: true})
+	player := &Player{CrosshairShareCode: shareCode}
+
+	settings := player.CrosshairSettings()
+	if settings == nil {
+		t.Fatalf("expected the share code %s to be decoded", shareCode)
+	}
+	if settings.Style != 4 || settings.Size != 2 || settings.Gap != -3 || settings.Thickness != 1 || !settings.IsCenterDotEnabled {
+		t.Errorf("unexpected crosshair settings %+v", settings)
+	}
+
+	for _, shareCode := range []string{"", "CSGO-invalid"} {
+		player.CrosshairShareCode = shareCode
+		if player.CrosshairSettings() != nil {
+			t.Errorf("expected no crosshair settings for the share code %q", shareCode)
+		}
+	}
+}
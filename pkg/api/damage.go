@@ -1,8 +1,6 @@
 package api
 
 import (
-	"fmt"
-
 	"github.com/akiver/cs-demo-analyzer/internal/math"
 	"github.com/akiver/cs-demo-analyzer/pkg/api/constants"
 	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
@@ -99,7 +97,7 @@ func newDamageMatchFrameKeyFromDamage(damage *Damage) damageMatchFrameKey {
 
 func newDamageFromGameEvent(analyzer *Analyzer, event events.PlayerHurt) *Damage {
 	if event.Weapon == nil {
-		fmt.Println("Player hurt event without weapon occurred")
+		analyzer.warn(constants.WarningKindMissingEntity, "Player hurt event without weapon occurred")
 		return nil
 	}
 	parser := analyzer.parser
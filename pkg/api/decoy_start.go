@@ -1,8 +1,7 @@
 package api
 
 import (
-	"fmt"
-
+	"github.com/akiver/cs-demo-analyzer/pkg/api/constants"
 	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
 	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/events"
 )
@@ -30,13 +29,13 @@ type DecoyStart struct {
 func newDecoyStartFromGameEvent(analyzer *Analyzer, event events.DecoyStart) *DecoyStart {
 	grenade := event.Grenade
 	if grenade == nil {
-		fmt.Println("Grenade nil in decoy start event")
+		analyzer.warn(constants.WarningKindMissingEntity, "Grenade nil in decoy start event")
 		return nil
 	}
 
 	thrower := event.Thrower
 	if thrower == nil {
-		fmt.Println("Thrower nil in decoy start event")
+		analyzer.warn(constants.WarningKindMissingEntity, "Thrower nil in decoy start event")
 		return nil
 	}
 
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"time"
+
+	d "github.com/akiver/cs-demo-analyzer/internal/demo"
+	"github.com/akiver/cs-demo-analyzer/pkg/api/constants"
+)
+
+// Metadata read from the demo header only, the demo is not parsed.
+// Tick count, tickrate, framerate and duration are not available for CS2 demos.
+type DemoHeader struct {
+	FilePath        string               `json:"filePath"`
+	FileName        string               `json:"fileName"`
+	Checksum        string               `json:"checksum"` // Empty for compressed demos, the whole archive would have to be decompressed
+	Game            constants.Game       `json:"game"`
+	Type            constants.DemoType   `json:"type"`
+	Source          constants.DemoSource `json:"source"`
+	SourceRule      string               `json:"sourceRule"` // Description of the rule that detected the source, empty if the source is unknown
+	MapName         string               `json:"mapName"`
+	ServerName      string               `json:"serverName"`
+	ClientName      string               `json:"clientName"`
+	Date            time.Time            `json:"date"`
+	NetworkProtocol int                  `json:"networkProtocol"`
+	BuildNumber     int                  `json:"buildNumber"` // CS2 only
+	TickCount       int                  `json:"tickCount"`
+	TickRate        float64              `json:"tickrate"`
+	FrameRate       float64              `json:"framerate"`
+	Duration        time.Duration        `json:"duration"`
+	ShareCode       string               `json:"shareCode"` // Valve demos only, the .info file must be next to the .dem file to be able to generate it
+}
+
+// Reads the demo header without parsing the demo, it's a lot faster than AnalyzeDemo.
+func InspectDemo(demoPath string) (*DemoHeader, error) {
+	demo, err := d.GetDemoFromPath(demoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	source, sourceRule := d.DetectDemoSource(demo)
+
+	return &DemoHeader{
+		FilePath:        demo.FilePath,
+		FileName:        demo.FileName,
+		Checksum:        demo.Checksum,
+		Game:            getDemoGame(demo),
+		Type:            demo.Type,
+		Source:          source,
+		SourceRule:      sourceRule,
+		MapName:         demo.MapName,
+		ServerName:      demo.ServerName,
+		ClientName:      demo.ClientName,
+		Date:            demo.Date,
+		NetworkProtocol: demo.NetworkProtocol,
+		BuildNumber:     demo.BuildNumber,
+		TickCount:       demo.TickCount,
+		TickRate:        demo.TickRate,
+		FrameRate:       demo.FrameRate,
+		Duration:        demo.Duration,
+		ShareCode:       demo.ShareCode,
+	}, nil
+}
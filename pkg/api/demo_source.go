@@ -1,7 +1,6 @@
 package api
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/akiver/cs-demo-analyzer/internal/slice"
@@ -23,5 +22,5 @@ func ValidateDemoSource(source constants.DemoSource) error {
 		return nil
 	}
 
-	return fmt.Errorf("invalid source provided, valid sources: %s", FormatValidDemoSources())
+	return newError(ErrorCodeInvalidArgument, "invalid source provided, valid sources: "+FormatValidDemoSources(), nil)
 }
@@ -0,0 +1,300 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+type FieldChange struct {
+	Field string `json:"field"`
+	A     any    `json:"a"`
+	B     any    `json:"b"`
+}
+
+type RowChange struct {
+	Key     string         `json:"key"`
+	Changes []*FieldChange `json:"changes"`
+}
+
+// Differences of a JSON array of the match (kills, damages...) or of the players.
+type TableDiff struct {
+	Table     string       `json:"table"`
+	RowCountA int          `json:"rowCountA"`
+	RowCountB int          `json:"rowCountB"`
+	Added     []string     `json:"added"`   // Keys of the rows only in B
+	Removed   []string     `json:"removed"` // Keys of the rows only in A
+	Changed   []*RowChange `json:"changed"`
+	// Number of changed rows by field, for the players it's the list of columns that changed.
+	ChangedFields map[string]int `json:"changedFields"`
+}
+
+// Differences between two analyses of the same demo, A is usually the reference.
+type MatchDiff struct {
+	Fields []*FieldChange `json:"fields"` // Match values that aren't tables, nested objects fields are separated by a dot
+	Tables []*TableDiff   `json:"tables"` // Only tables with differences
+}
+
+type DiffOptions struct {
+	// Match fields, tables or rows fields (JSON names) that are not compared, e.g. demoFilePath or analyzerConfig.
+	IgnoredFields []string
+}
+
+func (diff *MatchDiff) HasChanges() bool {
+	return len(diff.Fields) > 0 || len(diff.Tables) > 0
+}
+
+// Rows are aligned with these fields and the fields ending with SteamId, in this order.
+var diffKeyFields = []string{"number", "roundNumber", "frame", "tick", "steamId"}
+
+func decodeMatchJSON(reader io.Reader) (map[string]any, error) {
+	decoder := json.NewDecoder(reader)
+	// Keep SteamIDs and floats as they have been written.
+	decoder.UseNumber()
+	var match map[string]any
+	if err := decoder.Decode(&match); err != nil {
+		return nil, err
+	}
+
+	return match, nil
+}
+
+// Compares two JSON exports, arrays of objects are compared as tables and the players by SteamID.
+func DiffMatchExports(a io.Reader, b io.Reader, options DiffOptions) (*MatchDiff, error) {
+	matchA, err := decodeMatchJSON(a)
+	if err != nil {
+		return nil, newError(ErrorCodeInvalidArgument, "invalid JSON export A", err)
+	}
+	matchB, err := decodeMatchJSON(b)
+	if err != nil {
+		return nil, newError(ErrorCodeInvalidArgument, "invalid JSON export B", err)
+	}
+
+	return diffMatchValues(matchA, matchB, options), nil
+}
+
+func DiffMatchFiles(pathA string, pathB string, options DiffOptions) (*MatchDiff, error) {
+	readers := []io.Reader{}
+	for _, path := range []string{pathA, pathB} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, newError(ErrorCodeInvalidArgument, fmt.Sprintf("unable to read %q", path), err)
+		}
+		readers = append(readers, bytes.NewReader(data))
+	}
+
+	return DiffMatchExports(readers[0], readers[1], options)
+}
+
+// Compares two analyzed matches as their JSON exports, e.g. to review the effect of an AnalyzerConfig.
+func DiffMatches(a *Match, b *Match, options DiffOptions) (*MatchDiff, error) {
+	dataA, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	dataB, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return DiffMatchExports(bytes.NewReader(dataA), bytes.NewReader(dataB), options)
+}
+
+func diffMatchValues(matchA map[string]any, matchB map[string]any, options DiffOptions) *MatchDiff {
+	diff := &MatchDiff{Fields: []*FieldChange{}, Tables: []*TableDiff{}}
+	fieldsA := make(map[string]any)
+	fieldsB := make(map[string]any)
+
+	for _, name := range sortedUnionKeys(matchA, matchB) {
+		if slices.Contains(options.IgnoredFields, name) {
+			continue
+		}
+
+		valueA, valueB := matchA[name], matchB[name]
+		rowsA, isTableA := toDiffRows(valueA)
+		rowsB, isTableB := toDiffRows(valueB)
+		if isTableA && isTableB {
+			table := diffTable(name, rowsA, rowsB, options)
+			if len(table.Added) > 0 || len(table.Removed) > 0 || len(table.Changed) > 0 {
+				diff.Tables = append(diff.Tables, table)
+			}
+			continue
+		}
+
+		flattenDiffValue(name, valueA, fieldsA)
+		flattenDiffValue(name, valueB, fieldsB)
+	}
+
+	diff.Fields = diffFields(fieldsA, fieldsB, options)
+
+	return diff
+}
+
+type diffRow struct {
+	key    string
+	fields map[string]any
+}
+
+// Arrays of objects are tables, null is an empty table. Objects whose values are all objects (players by SteamID)
+// are tables too, the object key is the row key.
+// Tables are only compared with tables, a table and another value are compared as fields.
+func toDiffRows(value any) ([]*diffRow, bool) {
+	switch value := value.(type) {
+	case nil:
+		return []*diffRow{}, true
+	case []any:
+		rows := []*diffRow{}
+		for _, item := range value {
+			object, ok := item.(map[string]any)
+			if !ok {
+				return nil, false
+			}
+			rows = append(rows, newDiffRow("", object))
+		}
+		return rows, true
+	case map[string]any:
+		rows := []*diffRow{}
+		for _, key := range sortedUnionKeys(value, nil) {
+			object, ok := value[key].(map[string]any)
+			if !ok {
+				return nil, false
+			}
+			rows = append(rows, newDiffRow(key, object))
+		}
+		return rows, true
+	default:
+		return nil, false
+	}
+}
+
+func newDiffRow(key string, object map[string]any) *diffRow {
+	row := &diffRow{key: key, fields: make(map[string]any)}
+	flattenDiffValue("", object, row.fields)
+	if key != "" {
+		return row
+	}
+
+	keyFields := []string{}
+	for _, field := range diffKeyFields {
+		if value, exists := row.fields[field]; exists {
+			keyFields = append(keyFields, fmt.Sprintf("%s=%v", field, value))
+		}
+	}
+	for _, field := range sortedUnionKeys(row.fields, nil) {
+		if strings.HasSuffix(field, "SteamId") {
+			keyFields = append(keyFields, fmt.Sprintf("%s=%v", field, row.fields[field]))
+		}
+	}
+	row.key = strings.Join(keyFields, " ")
+
+	return row
+}
+
+// Nested objects are flattened with dotted names, other values (arrays included) are compared as a whole.
+func flattenDiffValue(name string, value any, fields map[string]any) {
+	object, ok := value.(map[string]any)
+	if !ok {
+		fields[name] = value
+		return
+	}
+
+	for key, nestedValue := range object {
+		if name != "" {
+			key = name + "." + key
+		}
+		flattenDiffValue(key, nestedValue, fields)
+	}
+}
+
+// Rows with the same key are aligned in their order of appearance.
+func indexDiffRows(rows []*diffRow) ([]string, map[string]*diffRow) {
+	keys := []string{}
+	rowsByKey := make(map[string]*diffRow)
+	occurrences := make(map[string]int)
+	for index, row := range rows {
+		key := row.key
+		if key == "" {
+			key = fmt.Sprintf("#%d", index)
+		}
+		occurrences[key]++
+		if occurrences[key] > 1 {
+			key = fmt.Sprintf("%s #%d", key, occurrences[key])
+		}
+		keys = append(keys, key)
+		rowsByKey[key] = row
+	}
+
+	return keys, rowsByKey
+}
+
+func diffTable(name string, rowsA []*diffRow, rowsB []*diffRow, options DiffOptions) *TableDiff {
+	table := &TableDiff{
+		Table:         name,
+		RowCountA:     len(rowsA),
+		RowCountB:     len(rowsB),
+		Added:         []string{},
+		Removed:       []string{},
+		Changed:       []*RowChange{},
+		ChangedFields: make(map[string]int),
+	}
+
+	keysA, rowsByKeyA := indexDiffRows(rowsA)
+	keysB, rowsByKeyB := indexDiffRows(rowsB)
+	for _, key := range keysA {
+		rowB, exists := rowsByKeyB[key]
+		if !exists {
+			table.Removed = append(table.Removed, key)
+			continue
+		}
+
+		changes := diffFields(rowsByKeyA[key].fields, rowB.fields, options)
+		if len(changes) == 0 {
+			continue
+		}
+		table.Changed = append(table.Changed, &RowChange{Key: key, Changes: changes})
+		for _, change := range changes {
+			table.ChangedFields[change.Field]++
+		}
+	}
+	for _, key := range keysB {
+		if _, exists := rowsByKeyA[key]; !exists {
+			table.Added = append(table.Added, key)
+		}
+	}
+
+	return table
+}
+
+func diffFields(fieldsA map[string]any, fieldsB map[string]any, options DiffOptions) []*FieldChange {
+	changes := []*FieldChange{}
+	for _, field := range sortedUnionKeys(fieldsA, fieldsB) {
+		if slices.Contains(options.IgnoredFields, field) {
+			continue
+		}
+		if !reflect.DeepEqual(fieldsA[field], fieldsB[field]) {
+			changes = append(changes, &FieldChange{Field: field, A: fieldsA[field], B: fieldsB[field]})
+		}
+	}
+
+	return changes
+}
+
+func sortedUnionKeys(a map[string]any, b map[string]any) []string {
+	keys := []string{}
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, exists := a[key]; !exists {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	return keys
+}
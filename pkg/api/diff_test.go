@@ -0,0 +1,102 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const diffTestExportA = `{
+	"checksum": "abc",
+	"tickCount": 1000,
+	"demoFilePath": "/a/demo.dem",
+	"teamA": {"name": "A", "score": 13},
+	"players": {
+		"76561198000000001": {"name": "foo", "kills": 20},
+		"76561198000000002": {"name": "bar", "kills": 10}
+	},
+	"kills": [
+		{"frame": 10, "tick": 20, "killerSteamId": 76561198000000001, "victimSteamId": 76561198000000002, "distance": 100.5},
+		{"frame": 30, "tick": 40, "killerSteamId": 76561198000000002, "victimSteamId": 76561198000000001, "distance": 10},
+		{"frame": 30, "tick": 40, "killerSteamId": 76561198000000002, "victimSteamId": 76561198000000001, "distance": 20}
+	],
+	"shots": null
+}`
+
+const diffTestExportB = `{
+	"checksum": "abc",
+	"tickCount": 1002,
+	"demoFilePath": "/b/demo.dem",
+	"teamA": {"name": "A", "score": 14},
+	"players": {
+		"76561198000000001": {"name": "foo", "kills": 21},
+		"76561198000000002": {"name": "bar", "kills": 10}
+	},
+	"kills": [
+		{"frame": 10, "tick": 20, "killerSteamId": 76561198000000001, "victimSteamId": 76561198000000002, "distance": 200},
+		{"frame": 30, "tick": 40, "killerSteamId": 76561198000000002, "victimSteamId": 76561198000000001, "distance": 10},
+		{"frame": 50, "tick": 60, "killerSteamId": 76561198000000001, "victimSteamId": 76561198000000002, "distance": 10}
+	],
+	"shots": []
+}`
+
+func TestDiffMatchExports(t *testing.T) {
+	diff, err := DiffMatchExports(strings.NewReader(diffTestExportA), strings.NewReader(diffTestExportB), DiffOptions{
+		IgnoredFields: []string{"demoFilePath"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error got %v", err)
+	}
+
+	fields := []string{}
+	for _, change := range diff.Fields {
+		fields = append(fields, change.Field)
+	}
+	if strings.Join(fields, ",") != "teamA.score,tickCount" {
+		t.Errorf("expected teamA.score and tickCount to change got %v", fields)
+	}
+
+	if len(diff.Tables) != 2 || diff.Tables[0].Table != "kills" || diff.Tables[1].Table != "players" {
+		t.Fatalf("expected kills and players differences got %d tables", len(diff.Tables))
+	}
+
+	kills := diff.Tables[0]
+	// The second kill at frame 30 is aligned with the second one of the same key.
+	expectedRemoved := "frame=30 tick=40 killerSteamId=76561198000000002 victimSteamId=76561198000000001 #2"
+	if len(kills.Removed) != 1 || kills.Removed[0] != expectedRemoved {
+		t.Errorf("expected removed %q got %v", expectedRemoved, kills.Removed)
+	}
+	expectedAdded := "frame=50 tick=60 killerSteamId=76561198000000001 victimSteamId=76561198000000002"
+	if len(kills.Added) != 1 || kills.Added[0] != expectedAdded {
+		t.Errorf("expected added %q got %v", expectedAdded, kills.Added)
+	}
+	if len(kills.Changed) != 1 || kills.Changed[0].Changes[0].Field != "distance" || kills.ChangedFields["distance"] != 1 {
+		t.Errorf("expected the distance of the first kill to change got %+v", kills.Changed)
+	}
+
+	players := diff.Tables[1]
+	if len(players.Changed) != 1 || players.Changed[0].Key != "76561198000000001" || players.ChangedFields["kills"] != 1 {
+		t.Errorf("expected the kills of the first player to change got %+v", players.Changed)
+	}
+	// Numbers are kept as they have been written.
+	data, _ := json.Marshal(players.Changed[0].Changes[0])
+	if string(data) != `{"field":"kills","a":20,"b":21}` {
+		t.Errorf("unexpected change %s", data)
+	}
+}
+
+func TestDiffMatchExports_NoChanges(t *testing.T) {
+	diff, err := DiffMatchExports(strings.NewReader(diffTestExportA), strings.NewReader(diffTestExportA), DiffOptions{})
+	if err != nil {
+		t.Fatalf("expected no error got %v", err)
+	}
+	if diff.HasChanges() {
+		t.Errorf("expected no changes got %+v", diff)
+	}
+
+	_, err = DiffMatchExports(strings.NewReader(diffTestExportA), strings.NewReader("{"), DiffOptions{})
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("expected an invalid argument error got %v", err)
+	}
+}
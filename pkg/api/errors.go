@@ -0,0 +1,122 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime/debug"
+)
+
+type ErrorCode string
+
+const (
+	ErrorCodeUnknown                     ErrorCode = "Unknown"
+	ErrorCodeInternal                    ErrorCode = "Internal"
+	ErrorCodeInvalidArgument             ErrorCode = "InvalidArgument"
+	ErrorCodeDemoNotFound                ErrorCode = "DemoNotFound"
+	ErrorCodeCorruptedDemo               ErrorCode = "CorruptedDemo"
+	ErrorCodeGameNotSupported            ErrorCode = "GameNotSupported"
+	ErrorCodeUnknownSource               ErrorCode = "UnknownSource"
+	ErrorCodeSourceNotSupported          ErrorCode = "SourceNotSupported"
+	ErrorCodePOVNotSupported             ErrorCode = "POVNotSupported"
+	ErrorCodeMissingGameEventDescriptors ErrorCode = "MissingGameEventDescriptors"
+	ErrorCodeCanceled                    ErrorCode = "Canceled"
+	ErrorCodeTimeout                     ErrorCode = "Timeout"
+	ErrorCodeValidationFailed            ErrorCode = "ValidationFailed"
+)
+
+// Error is returned by the analysis and the exports, use errors.As to get its code or errors.Is with the Err* values.
+// Two errors are considered equal by errors.Is when they have the same code.
+type Error struct {
+	Code    ErrorCode
+	Message string
+	Err     error  // Underlying error, may be nil
+	Stack   []byte // Stack trace of the recovered panic, Internal errors only
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Is(target error) bool {
+	targetError, ok := target.(*Error)
+	return ok && targetError.Code == e.Code
+}
+
+var (
+	ErrInternal                    = &Error{Code: ErrorCodeInternal, Message: "internal error"}
+	ErrInvalidArgument             = &Error{Code: ErrorCodeInvalidArgument, Message: "invalid argument"}
+	ErrDemoNotFound                = &Error{Code: ErrorCodeDemoNotFound, Message: "demo file not found"}
+	ErrCorruptedDemo               = &Error{Code: ErrorCodeCorruptedDemo, Message: "corrupted demo"}
+	ErrGameNotSupported            = &Error{Code: ErrorCodeGameNotSupported, Message: "csgo demos are not supported"}
+	ErrUnknownSource               = &Error{Code: ErrorCodeUnknownSource, Message: "unknown demo source, please specify the source with the -source flag"}
+	ErrSourceNotSupported          = &Error{Code: ErrorCodeSourceNotSupported, Message: "demo source not supported"}
+	ErrPOVNotSupported             = &Error{Code: ErrorCodePOVNotSupported, Message: "cs2 pov demos are not supported"}
+	ErrMissingGameEventDescriptors = &Error{Code: ErrorCodeMissingGameEventDescriptors, Message: "missing game event descriptors"}
+	ErrValidationFailed            = &Error{Code: ErrorCodeValidationFailed, Message: "validation failed"}
+)
+
+func newError(code ErrorCode, message string, err error) *Error {
+	return &Error{Code: code, Message: message, Err: err}
+}
+
+// Returns the code of the error, context errors are not wrapped and have their own codes.
+func GetErrorCode(err error) ErrorCode {
+	var apiError *Error
+	switch {
+	case err == nil:
+		return ""
+	case errors.As(err, &apiError):
+		return apiError.Code
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorCodeTimeout
+	case errors.Is(err, context.Canceled):
+		return ErrorCodeCanceled
+	default:
+		return ErrorCodeUnknown
+	}
+}
+
+// Used by event handlers to stop the parsing with an error, the panic is recovered by recoverAnalysisPanic.
+type abortAnalysis struct {
+	err error
+}
+
+// Must be deferred, converts a panic into an error.
+// The parser panics with io.ErrUnexpectedEOF or io.EOF when the demo is truncated in places it doesn't handle it.
+func recoverAnalysisPanic(err *error) {
+	value := recover()
+	if value == nil {
+		return
+	}
+
+	if abort, ok := value.(abortAnalysis); ok {
+		*err = abort.err
+		return
+	}
+
+	valueError, ok := value.(error)
+	if !ok {
+		valueError = fmt.Errorf("%v", value)
+	}
+	if errors.Is(valueError, io.ErrUnexpectedEOF) || errors.Is(valueError, io.EOF) {
+		*err = newError(ErrorCodeCorruptedDemo, ErrCorruptedDemo.Message, valueError)
+		return
+	}
+
+	*err = &Error{
+		Code:    ErrorCodeInternal,
+		Message: "unexpected panic during the analysis",
+		Err:     valueError,
+		Stack:   debug.Stack(),
+	}
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestError_IsMatchesCode(t *testing.T) {
+	err := fmt.Errorf("analysis failed: %w", newError(ErrorCodeSourceNotSupported, "cevo demos are not supported", nil))
+
+	if !errors.Is(err, ErrSourceNotSupported) {
+		t.Fatalf("expected error to match ErrSourceNotSupported")
+	}
+	if errors.Is(err, ErrUnknownSource) {
+		t.Fatalf("expected error to not match ErrUnknownSource")
+	}
+
+	var apiError *Error
+	if !errors.As(err, &apiError) {
+		t.Fatalf("expected error to be an *Error")
+	}
+	if apiError.Message != "cevo demos are not supported" {
+		t.Errorf("expected message %q got %q", "cevo demos are not supported", apiError.Message)
+	}
+}
+
+func TestGetErrorCode(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected ErrorCode
+	}{
+		{nil, ""},
+		{errors.New("boom"), ErrorCodeUnknown},
+		{ErrPOVNotSupported, ErrorCodePOVNotSupported},
+		{newError(ErrorCodeCorruptedDemo, ErrCorruptedDemo.Message, io.EOF), ErrorCodeCorruptedDemo},
+		{context.Canceled, ErrorCodeCanceled},
+		{fmt.Errorf("wrapped: %w", context.DeadlineExceeded), ErrorCodeTimeout},
+	}
+
+	for _, test := range tests {
+		if code := GetErrorCode(test.err); code != test.expected {
+			t.Errorf("expected code %q for error %v got %q", test.expected, test.err, code)
+		}
+	}
+}
+
+func recoverFrom(value any) (err error) {
+	defer recoverAnalysisPanic(&err)
+	panic(value)
+}
+
+func TestRecoverAnalysisPanic(t *testing.T) {
+	err := recoverFrom(abortAnalysis{err: ErrMissingGameEventDescriptors})
+	if err != ErrMissingGameEventDescriptors {
+		t.Errorf("expected ErrMissingGameEventDescriptors got %v", err)
+	}
+
+	err = recoverFrom(io.ErrUnexpectedEOF)
+	if !errors.Is(err, ErrCorruptedDemo) || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected a corrupted demo error wrapping io.ErrUnexpectedEOF got %v", err)
+	}
+
+	err = recoverFrom("index out of range")
+	var apiError *Error
+	if !errors.As(err, &apiError) || apiError.Code != ErrorCodeInternal {
+		t.Fatalf("expected an internal error got %v", err)
+	}
+	if len(apiError.Stack) == 0 {
+		t.Errorf("expected the stack trace to be set")
+	}
+	if err.Error() != "unexpected panic during the analysis: index out of range" {
+		t.Errorf("unexpected message %q", err.Error())
+	}
+}
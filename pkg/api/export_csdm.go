@@ -7,7 +7,6 @@
 package api
 
 import (
-	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -22,7 +21,7 @@ import (
 
 func exportMatchForCSDM(match *Match, outputPath string) error {
 	if stat, err := os.Stat(outputPath); err != nil || !stat.IsDir() {
-		return errors.New("incorrect output provided, make sure it's a folder that exists and you have write access")
+		return newError(ErrorCodeInvalidArgument, "incorrect output provided, make sure it's a folder that exists and you have write access", nil)
 	}
 
 	outputPath = outputPath + string(os.PathSeparator) + match.DemoFileName
@@ -1032,5 +1031,5 @@ func exportMatchForCSDM(match *Match, outputPath string) error {
 
 	wg.Wait()
 
-	return nil
+	return writeAnalyzerConfigFile(match, outputPath+"_analyzer_config.json")
 }
@@ -1,9 +1,9 @@
 package api
 
 import (
-	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -11,12 +11,13 @@ import (
 	"github.com/akiver/cs-demo-analyzer/internal/converters"
 	"github.com/akiver/cs-demo-analyzer/internal/csv"
 	"github.com/akiver/cs-demo-analyzer/internal/slice"
+	"github.com/akiver/cs-demo-analyzer/pkg/api/plugin"
 	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
 )
 
 func exportMatchToCSV(match *Match, outputPath string) error {
 	if stat, err := os.Stat(outputPath); err != nil || !stat.IsDir() {
-		return errors.New("incorrect output provided, make sure it's a folder that exists and you have write access")
+		return newError(ErrorCodeInvalidArgument, "incorrect output provided, make sure it's a folder that exists and you have write access", nil)
 	}
 
 	outputPath = outputPath + string(os.PathSeparator) + match.DemoFileName
@@ -53,6 +54,13 @@ func exportMatchToCSV(match *Match, outputPath string) error {
 			"overtime count",
 			"max rounds",
 			"has vac live ban",
+			"ended by surrender",
+			"valve match id",
+			"valve reservation id",
+			"valve server ip",
+			"valve tv port",
+			"valve score mismatch rounds",
+			"validation confidence",
 		}
 
 		winnerName := ""
@@ -61,6 +69,18 @@ func exportMatchToCSV(match *Match, outputPath string) error {
 			winnerName = match.Winner.Name
 			winnerSide = *match.Winner.CurrentSide
 		}
+		valveMatchInfo := match.ValveMatchInfo
+		if valveMatchInfo == nil {
+			valveMatchInfo = &ValveMatchInfo{}
+		}
+		scoreMismatchRounds := make([]string, 0, len(valveMatchInfo.ScoreMismatchRoundNumbers))
+		for _, roundNumber := range valveMatchInfo.ScoreMismatchRoundNumbers {
+			scoreMismatchRounds = append(scoreMismatchRounds, converters.IntToString(roundNumber))
+		}
+		validationConfidence := ""
+		if match.Validation != nil {
+			validationConfidence = converters.Float64ToString(match.Validation.Confidence)
+		}
 		line := []string{
 			match.Checksum,
 			match.Game.String(),
@@ -92,6 +112,13 @@ func exportMatchToCSV(match *Match, outputPath string) error {
 			converters.IntToString(match.OvertimeCount),
 			converters.IntToString(match.MaxRounds),
 			converters.BoolToString(match.HasVacLiveBan),
+			converters.BoolToString(match.EndedBySurrender),
+			converters.Uint64ToString(valveMatchInfo.MatchID),
+			converters.Uint64ToString(valveMatchInfo.ReservationID),
+			valveMatchInfo.ServerIP,
+			converters.Uint32ToString(valveMatchInfo.TvPort),
+			strings.Join(scoreMismatchRounds, ";"),
+			validationConfidence,
 		}
 
 		csv.WriteLinesIntoCsvFile(outputPath+"_match.csv", [][]string{
@@ -208,6 +235,18 @@ func exportMatchToCSV(match *Match, outputPath string) error {
 			"wallbang kill count",
 			"awp hold kill count",
 			"awp hold death count",
+			"bhop chain count",
+			"jiggle peek count",
+			"wide swing count",
+			"crouch spam count",
+			"jump shot count",
+			"bomb carry time",
+			"rounds played",
+			"kill per round played",
+			"assist per round played",
+			"death per round played",
+			"damage per round played",
+			"utility damage per round played",
 			"team attack damage",
 			"team utility damage",
 			"team flash duration",
@@ -231,6 +270,12 @@ func exportMatchToCSV(match *Match, outputPath string) error {
 			"counter-strafing perfect rate",
 			"match checksum",
 		}
+		playerMetrics := slice.Filter(match.Metrics, func(table *MetricTable, index int) bool {
+			return table.IsPlayerMetric
+		})
+		for _, table := range playerMetrics {
+			header = append(header, table.Name)
+		}
 
 		lines := [][]string{header}
 		for _, player := range match.Players() {
@@ -309,6 +354,18 @@ func exportMatchToCSV(match *Match, outputPath string) error {
 				converters.IntToString(player.WallbangKillCount()),
 				converters.IntToString(player.AwpHoldKillCount()),
 				converters.IntToString(player.AwpHoldDeathCount()),
+				converters.IntToString(player.BhopChainCount()),
+				converters.IntToString(player.JigglePeekCount()),
+				converters.IntToString(player.WideSwingCount()),
+				converters.IntToString(player.CrouchSpamCount()),
+				converters.IntToString(player.JumpShotCount()),
+				converters.Float64ToString(player.BombCarryTime()),
+				converters.IntToString(player.RoundsPlayedCount()),
+				converters.Float32ToString(player.AverageKillPerRoundPlayed()),
+				converters.Float32ToString(player.AverageAssistPerRoundPlayed()),
+				converters.Float32ToString(player.AverageDeathPerRoundPlayed()),
+				converters.Float32ToString(player.AverageDamagePerRoundPlayed()),
+				converters.Float32ToString(player.UtilityDamagePerRoundPlayed()),
 				converters.IntToString(player.TeamAttackDamage()),
 				converters.IntToString(player.TeamUtilityDamage()),
 				converters.Float32ToString(player.TeamFlashDuration()),
@@ -332,6 +389,9 @@ func exportMatchToCSV(match *Match, outputPath string) error {
 				converters.Float32ToString(player.CounterStrafingPerfectRate()),
 				match.Checksum,
 			}
+			for _, table := range playerMetrics {
+				line = append(line, table.playerValueText(player.SteamID64))
+			}
 			lines = append(lines, line)
 		}
 
@@ -445,6 +505,7 @@ func exportMatchToCSV(match *Match, outputPath string) error {
 			"view punch angle x",
 			"view punch angle y",
 			"is player running",
+			"is player airborne",
 			"match checksum",
 		}
 
@@ -478,6 +539,7 @@ func exportMatchToCSV(match *Match, outputPath string) error {
 				converters.Float64ToString(shot.ViewPunchAngleX),
 				converters.Float64ToString(shot.ViewPunchAngleY),
 				converters.BoolToString(shot.IsPlayerRunning),
+				converters.BoolToString(shot.IsPlayerAirborne),
 				match.Checksum,
 			}
 			lines = append(lines, line)
@@ -896,11 +958,21 @@ func exportMatchToCSV(match *Match, outputPath string) error {
 			"is no scope",
 			"is killer running",
 			"distance",
+			"weapon paint kit",
+			"weapon wear",
+			"weapon wear name",
+			"weapon seed",
+			"weapon stattrak",
+			"weapon custom name",
 			"match checksum",
 		}
 
 		lines := [][]string{header}
 		for _, kill := range match.Kills {
+			skin := kill.WeaponSkin
+			if skin == nil {
+				skin = &WeaponSkin{StatTrak: -1}
+			}
 			line := []string{
 				converters.IntToString(kill.Frame),
 				converters.IntToString(kill.Tick),
@@ -945,6 +1017,12 @@ func exportMatchToCSV(match *Match, outputPath string) error {
 				converters.BoolToString(kill.IsNoScope),
 				converters.BoolToString(kill.IsKillerRunning),
 				converters.Float32ToString(kill.Distance),
+				converters.IntToString(skin.PaintKit),
+				converters.Float32ToString(skin.Wear),
+				skin.WearName(),
+				converters.IntToString(skin.Seed),
+				converters.IntToString(skin.StatTrak),
+				skin.CustomName,
 				match.Checksum,
 			}
 			lines = append(lines, line)
@@ -1954,6 +2032,678 @@ func exportMatchToCSV(match *Match, outputPath string) error {
 		csv.WriteLinesIntoCsvFile(outputPath+"_awp_hold_deaths.csv", lines)
 	}
 
+	var writeMovementEvents = func() {
+		header := []string{
+			"frame",
+			"tick",
+			"end frame",
+			"end tick",
+			"round",
+			"type",
+			"player name",
+			"player steamid",
+			"player side",
+			"player team name",
+			"count",
+			"weapon name",
+			"match checksum",
+		}
+		lines := [][]string{header}
+
+		for _, event := range match.MovementEvents {
+			line := []string{
+				converters.IntToString(event.Frame),
+				converters.IntToString(event.Tick),
+				converters.IntToString(event.EndFrame),
+				converters.IntToString(event.EndTick),
+				converters.IntToString(event.RoundNumber),
+				event.Type.String(),
+				event.PlayerName,
+				converters.Uint64ToString(event.PlayerSteamID64),
+				converters.TeamToString(event.PlayerSide),
+				event.PlayerTeamName,
+				converters.IntToString(event.Count),
+				event.WeaponName.String(),
+				match.Checksum,
+			}
+			lines = append(lines, line)
+		}
+
+		csv.WriteLinesIntoCsvFile(outputPath+"_movement_events.csv", lines)
+	}
+
+	var writePlayerRoundMovements = func() {
+		header := []string{
+			"round",
+			"player name",
+			"player steamid",
+			"player side",
+			"player team name",
+			"time alive",
+			"distance travelled",
+			"average speed",
+			"rotation count",
+			"bomb carry time",
+			"match checksum",
+		}
+		lines := [][]string{header}
+
+		for _, movement := range match.PlayerRoundMovements {
+			line := []string{
+				converters.IntToString(movement.RoundNumber),
+				movement.PlayerName,
+				converters.Uint64ToString(movement.PlayerSteamID64),
+				converters.TeamToString(movement.PlayerSide),
+				movement.PlayerTeamName,
+				converters.Float64ToString(movement.TimeAliveSeconds),
+				converters.Float64ToString(movement.DistanceTravelled),
+				converters.Float64ToString(movement.AverageSpeed()),
+				converters.IntToString(movement.RotationCount),
+				converters.Float64ToString(movement.BombCarryTime),
+				match.Checksum,
+			}
+			lines = append(lines, line)
+		}
+
+		csv.WriteLinesIntoCsvFile(outputPath+"_player_round_movements.csv", lines)
+	}
+
+	var writePlayerRoundZones = func() {
+		header := []string{
+			"round",
+			"player name",
+			"player steamid",
+			"player side",
+			"player team name",
+			"zone",
+			"time spent",
+			"match checksum",
+		}
+		lines := [][]string{header}
+
+		for _, movement := range match.PlayerRoundMovements {
+			zones := make([]string, 0, len(movement.ZoneTimes))
+			for zone := range movement.ZoneTimes {
+				zones = append(zones, zone)
+			}
+			sort.Strings(zones)
+
+			for _, zone := range zones {
+				line := []string{
+					converters.IntToString(movement.RoundNumber),
+					movement.PlayerName,
+					converters.Uint64ToString(movement.PlayerSteamID64),
+					converters.TeamToString(movement.PlayerSide),
+					movement.PlayerTeamName,
+					zone,
+					converters.Float64ToString(movement.ZoneTimes[zone]),
+					match.Checksum,
+				}
+				lines = append(lines, line)
+			}
+		}
+
+		csv.WriteLinesIntoCsvFile(outputPath+"_player_round_zones.csv", lines)
+	}
+
+	var writeTeamSetups = func() {
+		header := []string{
+			"frame",
+			"tick",
+			"round",
+			"offset seconds",
+			"team name",
+			"side",
+			"alive player count",
+			"signature",
+			"player name",
+			"player steamid",
+			"zone",
+			"x",
+			"y",
+			"z",
+			"match checksum",
+		}
+		lines := [][]string{header}
+
+		for _, setup := range match.TeamSetups {
+			for _, playerZone := range setup.PlayerZones {
+				line := []string{
+					converters.IntToString(setup.Frame),
+					converters.IntToString(setup.Tick),
+					converters.IntToString(setup.RoundNumber),
+					converters.Float64ToString(setup.OffsetSeconds),
+					setup.TeamName,
+					converters.TeamToString(setup.Side),
+					converters.IntToString(setup.AlivePlayerCount),
+					setup.Signature,
+					playerZone.Name,
+					converters.Uint64ToString(playerZone.SteamID64),
+					playerZone.Zone,
+					converters.Float64ToString(playerZone.X),
+					converters.Float64ToString(playerZone.Y),
+					converters.Float64ToString(playerZone.Z),
+					match.Checksum,
+				}
+				lines = append(lines, line)
+			}
+		}
+
+		csv.WriteLinesIntoCsvFile(outputPath+"_setups.csv", lines)
+	}
+
+	var writeSetupClusters = func() {
+		header := []string{
+			"team name",
+			"side",
+			"offset seconds",
+			"signature",
+			"round count",
+			"win count",
+			"frequency",
+			"win rate",
+			"match checksum",
+		}
+		lines := [][]string{header}
+
+		for _, cluster := range match.SetupClusters() {
+			line := []string{
+				cluster.TeamName,
+				converters.TeamToString(cluster.Side),
+				converters.Float64ToString(cluster.OffsetSeconds),
+				cluster.Signature,
+				converters.IntToString(cluster.RoundCount),
+				converters.IntToString(cluster.WinCount),
+				converters.Float32ToString(cluster.Frequency),
+				converters.Float32ToString(cluster.WinRate),
+				match.Checksum,
+			}
+			lines = append(lines, line)
+		}
+
+		csv.WriteLinesIntoCsvFile(outputPath+"_setup_clusters.csv", lines)
+	}
+
+	var writeBombEvents = func() {
+		header := []string{
+			"frame",
+			"tick",
+			"round",
+			"type",
+			"player name",
+			"player steamid",
+			"is player controlling bot",
+			"previous carrier name",
+			"previous carrier steamid",
+			"ground time",
+			"killer name",
+			"killer steamid",
+			"victim name",
+			"victim steamid",
+			"x",
+			"y",
+			"z",
+			"match checksum",
+		}
+		lines := [][]string{header}
+
+		for _, event := range match.BombEvents {
+			line := []string{
+				converters.IntToString(event.Frame),
+				converters.IntToString(event.Tick),
+				converters.IntToString(event.RoundNumber),
+				event.Type.String(),
+				event.PlayerName,
+				converters.Uint64ToString(event.PlayerSteamID64),
+				converters.BoolToString(event.IsPlayerControllingBot),
+				event.PreviousCarrierName,
+				converters.Uint64ToString(event.PreviousCarrierSteamID64),
+				converters.Float64ToString(event.GroundTimeSeconds),
+				event.KillerName,
+				converters.Uint64ToString(event.KillerSteamID64),
+				event.VictimName,
+				converters.Uint64ToString(event.VictimSteamID64),
+				converters.Float64ToString(event.X),
+				converters.Float64ToString(event.Y),
+				converters.Float64ToString(event.Z),
+				match.Checksum,
+			}
+			lines = append(lines, line)
+		}
+
+		csv.WriteLinesIntoCsvFile(outputPath+"_bomb_events.csv", lines)
+	}
+
+	var writeTimeouts = func() {
+		header := []string{
+			"frame",
+			"tick",
+			"end frame",
+			"end tick",
+			"round",
+			"type",
+			"source",
+			"team name",
+			"side",
+			"requester name",
+			"requester steamid",
+			"duration",
+			"match checksum",
+		}
+		lines := [][]string{header}
+
+		for _, timeout := range match.Timeouts {
+			line := []string{
+				converters.IntToString(timeout.Frame),
+				converters.IntToString(timeout.Tick),
+				converters.IntToString(timeout.EndFrame),
+				converters.IntToString(timeout.EndTick),
+				converters.IntToString(timeout.RoundNumber),
+				timeout.Type.String(),
+				timeout.Source.String(),
+				timeout.TeamName,
+				converters.TeamToString(timeout.Side),
+				timeout.RequesterName,
+				converters.Uint64ToString(timeout.RequesterSteamID64),
+				converters.Float64ToString(timeout.DurationSeconds(match.TickRate)),
+				match.Checksum,
+			}
+			lines = append(lines, line)
+		}
+
+		csv.WriteLinesIntoCsvFile(outputPath+"_timeouts.csv", lines)
+	}
+
+	var writePlayerSessions = func() {
+		header := []string{
+			"frame",
+			"tick",
+			"round",
+			"type",
+			"player name",
+			"player steamid",
+			"old side",
+			"new side",
+			"bot name",
+			"match checksum",
+		}
+		lines := [][]string{header}
+
+		for _, event := range match.PlayerSessionEvents {
+			line := []string{
+				converters.IntToString(event.Frame),
+				converters.IntToString(event.Tick),
+				converters.IntToString(event.RoundNumber),
+				event.Type.String(),
+				event.PlayerName,
+				converters.Uint64ToString(event.PlayerSteamID64),
+				converters.TeamToString(event.OldSide),
+				converters.TeamToString(event.NewSide),
+				event.BotName,
+				match.Checksum,
+			}
+			lines = append(lines, line)
+		}
+
+		csv.WriteLinesIntoCsvFile(outputPath+"_player_sessions.csv", lines)
+	}
+
+	var writeVotes = func() {
+		header := []string{
+			"frame",
+			"tick",
+			"end frame",
+			"end tick",
+			"round",
+			"type",
+			"side",
+			"caller name",
+			"caller steamid",
+			"target name",
+			"target steamid",
+			"yes count",
+			"no count",
+			"potential vote count",
+			"outcome",
+			"fail reason",
+			"match checksum",
+		}
+		lines := [][]string{header}
+
+		for _, vote := range match.Votes {
+			line := []string{
+				converters.IntToString(vote.Frame),
+				converters.IntToString(vote.Tick),
+				converters.IntToString(vote.EndFrame),
+				converters.IntToString(vote.EndTick),
+				converters.IntToString(vote.RoundNumber),
+				vote.Type.String(),
+				converters.TeamToString(vote.Side),
+				vote.CallerName,
+				converters.Uint64ToString(vote.CallerSteamID64),
+				vote.TargetName,
+				converters.Uint64ToString(vote.TargetSteamID64),
+				converters.IntToString(vote.YesCount),
+				converters.IntToString(vote.NoCount),
+				converters.IntToString(vote.PotentialVoteCount),
+				vote.Outcome.String(),
+				converters.IntToString(int(vote.FailReason)),
+				match.Checksum,
+			}
+			lines = append(lines, line)
+		}
+
+		csv.WriteLinesIntoCsvFile(outputPath+"_votes.csv", lines)
+	}
+
+	var writeVoiceActivities = func() {
+		header := []string{
+			"start frame",
+			"start tick",
+			"end frame",
+			"end tick",
+			"round",
+			"player name",
+			"player steamid",
+			"player side",
+			"is player alive",
+			"packet count",
+			"duration",
+			"match checksum",
+		}
+		lines := [][]string{header}
+
+		for _, activity := range match.VoiceActivities {
+			line := []string{
+				converters.IntToString(activity.StartFrame),
+				converters.IntToString(activity.StartTick),
+				converters.IntToString(activity.EndFrame),
+				converters.IntToString(activity.EndTick),
+				converters.IntToString(activity.RoundNumber),
+				activity.PlayerName,
+				converters.Uint64ToString(activity.PlayerSteamID64),
+				converters.TeamToString(activity.PlayerSide),
+				converters.BoolToString(activity.IsPlayerAlive),
+				converters.IntToString(activity.PacketCount),
+				converters.Float64ToString(activity.DurationSeconds(match.TickRate)),
+				match.Checksum,
+			}
+			lines = append(lines, line)
+		}
+
+		csv.WriteLinesIntoCsvFile(outputPath+"_voice_activity.csv", lines)
+	}
+
+	var writeLoadouts = func() {
+		header := []string{
+			"frame",
+			"tick",
+			"round",
+			"player name",
+			"player steamid",
+			"player side",
+			"type",
+			"weapon name",
+			"item definition index",
+			"model hash",
+			"paint kit",
+			"wear",
+			"wear name",
+			"seed",
+			"stattrak",
+			"custom name",
+			"stickers",
+			"match checksum",
+		}
+		lines := [][]string{header}
+
+		for _, item := range match.LoadoutItems {
+			skin := item.Skin
+			if skin == nil {
+				skin = &WeaponSkin{StatTrak: -1}
+			}
+			line := []string{
+				converters.IntToString(item.Frame),
+				converters.IntToString(item.Tick),
+				converters.IntToString(item.RoundNumber),
+				item.PlayerName,
+				converters.Uint64ToString(item.PlayerSteamID64),
+				converters.TeamToString(item.PlayerSide),
+				item.Type.String(),
+				item.WeaponName.String(),
+				converters.IntToString(item.ItemDefinitionIndex),
+				converters.Uint64ToString(item.ModelHash),
+				converters.IntToString(skin.PaintKit),
+				converters.Float32ToString(skin.Wear),
+				skin.WearName(),
+				converters.IntToString(skin.Seed),
+				converters.IntToString(skin.StatTrak),
+				skin.CustomName,
+				skin.StickersString(),
+				match.Checksum,
+			}
+			lines = append(lines, line)
+		}
+
+		csv.WriteLinesIntoCsvFile(outputPath+"_loadouts.csv", lines)
+	}
+
+	var writeCrosshairs = func() {
+		header := []string{
+			"steamid",
+			"name",
+			"share code",
+			"style",
+			"size",
+			"gap",
+			"thickness",
+			"color",
+			"red",
+			"green",
+			"blue",
+			"alpha",
+			"alpha enabled",
+			"outline enabled",
+			"outline thickness",
+			"center dot enabled",
+			"t style enabled",
+			"follow recoil enabled",
+			"deployed weapon gap enabled",
+			"fixed gap",
+			"split distance",
+			"inner split alpha",
+			"outer split alpha",
+			"split size ratio",
+			"match checksum",
+		}
+		lines := [][]string{header}
+
+		for _, player := range match.Players() {
+			settings := player.CrosshairSettings()
+			if settings == nil {
+				continue
+			}
+			line := []string{
+				converters.Uint64ToString(player.SteamID64),
+				player.Name,
+				player.CrosshairShareCode,
+				converters.IntToString(settings.Style),
+				converters.Float64ToString(settings.Size),
+				converters.Float64ToString(settings.Gap),
+				converters.Float64ToString(settings.Thickness),
+				converters.IntToString(settings.Color),
+				converters.IntToString(int(settings.Red)),
+				converters.IntToString(int(settings.Green)),
+				converters.IntToString(int(settings.Blue)),
+				converters.IntToString(int(settings.Alpha)),
+				converters.BoolToString(settings.IsAlphaEnabled),
+				converters.BoolToString(settings.IsOutlineEnabled),
+				converters.Float64ToString(settings.OutlineThickness),
+				converters.BoolToString(settings.IsCenterDotEnabled),
+				converters.BoolToString(settings.IsTStyleEnabled),
+				converters.BoolToString(settings.IsFollowRecoilEnabled),
+				converters.BoolToString(settings.IsDeployedWeaponGapEnabled),
+				converters.Float64ToString(settings.FixedGap),
+				converters.IntToString(settings.SplitDistance),
+				converters.Float64ToString(settings.InnerSplitAlpha),
+				converters.Float64ToString(settings.OuterSplitAlpha),
+				converters.Float64ToString(settings.SplitSizeRatio),
+				match.Checksum,
+			}
+			lines = append(lines, line)
+		}
+
+		csv.WriteLinesIntoCsvFile(outputPath+"_crosshairs.csv", lines)
+	}
+
+	var writeValveMatchInfoPlayers = func() {
+		if match.ValveMatchInfo == nil {
+			return
+		}
+
+		header := []string{
+			"account id",
+			"steamid",
+			"name",
+			"kill count",
+			"assist count",
+			"death count",
+			"score",
+			"mvp count",
+			"headshot count",
+			"rank id",
+			"rank type id",
+			"win count",
+			"match checksum",
+		}
+		lines := [][]string{header}
+
+		for _, player := range match.ValveMatchInfo.Players {
+			line := []string{
+				converters.Uint32ToString(player.AccountID),
+				converters.Uint64ToString(player.SteamID64),
+				player.Name,
+				converters.IntToString(player.KillCount),
+				converters.IntToString(player.AssistCount),
+				converters.IntToString(player.DeathCount),
+				converters.IntToString(player.Score),
+				converters.IntToString(player.MvpCount),
+				converters.IntToString(player.HeadshotCount),
+				converters.IntToString(player.RankID),
+				converters.IntToString(player.RankTypeID),
+				converters.IntToString(player.WinCount),
+				match.Checksum,
+			}
+			lines = append(lines, line)
+		}
+
+		csv.WriteLinesIntoCsvFile(outputPath+"_valve_players.csv", lines)
+	}
+
+	var writePlayerRoles = func() {
+		header := []string{
+			"steamid",
+			"name",
+			"team name",
+			"side",
+			"round count",
+			"primary role",
+			"secondary role",
+			"entry score",
+			"lurker score",
+			"awper score",
+			"anchor score",
+			"support score",
+			"opening duel count",
+			"awp round share",
+			"average nearest teammate distance",
+			"bombsite occupancy",
+			"utility thrown count",
+			"flash assist count",
+			"match checksum",
+		}
+		lines := [][]string{header}
+
+		for _, evidence := range match.PlayerRoles() {
+			line := []string{
+				converters.Uint64ToString(evidence.SteamID64),
+				evidence.Name,
+				evidence.TeamName,
+				converters.TeamToString(evidence.Side),
+				converters.IntToString(evidence.RoundCount),
+				evidence.PrimaryRole.String(),
+				evidence.SecondaryRole.String(),
+				converters.Float64ToString(evidence.EntryScore),
+				converters.Float64ToString(evidence.LurkerScore),
+				converters.Float64ToString(evidence.AwperScore),
+				converters.Float64ToString(evidence.AnchorScore),
+				converters.Float64ToString(evidence.SupportScore),
+				converters.IntToString(evidence.OpeningDuelCount),
+				converters.Float64ToString(evidence.AwpRoundShare),
+				converters.Float64ToString(evidence.AverageNearestTeammateDistance),
+				converters.Float64ToString(evidence.BombsiteOccupancy),
+				converters.IntToString(evidence.UtilityThrownCount),
+				converters.IntToString(evidence.FlashAssistCount),
+				match.Checksum,
+			}
+			lines = append(lines, line)
+		}
+
+		csv.WriteLinesIntoCsvFile(outputPath+"_player_roles.csv", lines)
+	}
+
+	var writeValidation = func() {
+		if match.Validation == nil {
+			return
+		}
+
+		header := []string{
+			"check",
+			"passed",
+			"details",
+			"match checksum",
+		}
+		lines := [][]string{header}
+
+		for _, result := range match.Validation.Checks {
+			line := []string{
+				result.Check.String(),
+				converters.BoolToString(result.Passed),
+				result.Details,
+				match.Checksum,
+			}
+			lines = append(lines, line)
+		}
+
+		csv.WriteLinesIntoCsvFile(outputPath+"_validation.csv", lines)
+	}
+
+	var writeWarnings = func() {
+		header := []string{
+			"frame",
+			"tick",
+			"round",
+			"kind",
+			"message",
+			"match checksum",
+		}
+		lines := [][]string{header}
+
+		for _, warning := range match.Warnings {
+			line := []string{
+				converters.IntToString(warning.Frame),
+				converters.IntToString(warning.Tick),
+				converters.IntToString(warning.RoundNumber),
+				warning.Kind.String(),
+				warning.Message,
+				match.Checksum,
+			}
+			lines = append(lines, line)
+		}
+
+		csv.WriteLinesIntoCsvFile(outputPath+"_warnings.csv", lines)
+	}
+
 	var functions = []func(){
 		writeMatch,
 		writeTeams,
@@ -1991,6 +2741,32 @@ func exportMatchToCSV(match *Match, outputPath string) error {
 		writePlayerButtons,
 		writeFootsteps,
 		writeAwpHoldDeaths,
+		writeMovementEvents,
+		writePlayerRoundMovements,
+		writePlayerRoundZones,
+		writeTeamSetups,
+		writeSetupClusters,
+		writePlayerRoles,
+		writeBombEvents,
+		writeTimeouts,
+		writePlayerSessions,
+		writeVotes,
+		writeVoiceActivities,
+		writeLoadouts,
+		writeCrosshairs,
+		writeValveMatchInfoPlayers,
+		writeWarnings,
+		writeValidation,
+	}
+	for _, table := range match.CustomTables {
+		functions = append(functions, func() {
+			writeCustomTable(match, table, outputPath)
+		})
+	}
+	for _, table := range match.Metrics {
+		functions = append(functions, func() {
+			writeMetricTable(match, table, outputPath)
+		})
 	}
 	var wg sync.WaitGroup
 
@@ -2004,5 +2780,46 @@ func exportMatchToCSV(match *Match, outputPath string) error {
 
 	wg.Wait()
 
-	return nil
+	return writeAnalyzerConfigFile(match, outputPath+"_analyzer_config.json")
+}
+
+func writeCustomTable(match *Match, table *plugin.Table, outputPath string) {
+	header := []string{
+		"frame",
+		"tick",
+		"round",
+	}
+	header = append(header, table.Columns...)
+	header = append(header, "match checksum")
+	lines := [][]string{header}
+
+	for _, row := range table.Rows {
+		line := []string{
+			converters.IntToString(row.Frame),
+			converters.IntToString(row.Tick),
+			converters.IntToString(row.RoundNumber),
+		}
+		for _, value := range row.Values {
+			line = append(line, converters.AnyToString(value))
+		}
+		line = append(line, match.Checksum)
+		lines = append(lines, line)
+	}
+
+	// The plugin prefix prevents collisions with built-in files, e.g. the table roles of a plugin named player.
+	csv.WriteLinesIntoCsvFile(outputPath+"_plugin_"+table.Plugin+"_"+table.Name+".csv", lines)
+}
+
+func writeMetricTable(match *Match, table *MetricTable, outputPath string) {
+	header := append([]string{}, table.Columns...)
+	header = append(header, "match checksum")
+	lines := [][]string{header}
+
+	for _, row := range table.Rows {
+		line := append([]string{}, row.Keys...)
+		line = append(line, table.formatValue(row.Value), match.Checksum)
+		lines = append(lines, line)
+	}
+
+	csv.WriteLinesIntoCsvFile(outputPath+"_metric_"+table.Name+".csv", lines)
 }
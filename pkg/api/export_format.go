@@ -1,7 +1,6 @@
 package api
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/akiver/cs-demo-analyzer/internal/slice"
@@ -23,5 +22,5 @@ func ValidateExportFormat(format constants.ExportFormat) error {
 		return nil
 	}
 
-	return fmt.Errorf("invalid format provided, valid formats: %s", FormatValidExportFormats())
+	return newError(ErrorCodeInvalidArgument, "invalid format provided, valid formats: "+FormatValidExportFormats(), nil)
 }
@@ -2,7 +2,6 @@ package api
 
 import (
 	"encoding/json"
-	"errors"
 	"os"
 )
 
@@ -13,7 +12,7 @@ func buildOutputFilePath(match *Match, outputPath string) (string, error) {
 
 	stat, err := os.Stat(outputPath)
 	if err != nil {
-		return "", errors.New("invalid output provided, make sure the path exists and you have write access")
+		return "", newError(ErrorCodeInvalidArgument, "invalid output provided, make sure the path exists and you have write access", nil)
 	}
 
 	if stat.IsDir() {
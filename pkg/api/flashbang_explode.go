@@ -1,8 +1,7 @@
 package api
 
 import (
-	"fmt"
-
+	"github.com/akiver/cs-demo-analyzer/pkg/api/constants"
 	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
 	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/events"
 )
@@ -30,13 +29,13 @@ type FlashbangExplode struct {
 func newFlashbangExplodeFromGameEvent(analyzer *Analyzer, event events.FlashExplode) *FlashbangExplode {
 	grenade := event.Grenade
 	if grenade == nil {
-		fmt.Println("Grenade nil in flashbang explode event")
+		analyzer.warn(constants.WarningKindMissingEntity, "Grenade nil in flashbang explode event")
 		return nil
 	}
 
 	thrower := event.Thrower
 	if thrower == nil {
-		fmt.Println("Thrower nil in flashbang explode event")
+		analyzer.warn(constants.WarningKindMissingEntity, "Thrower nil in flashbang explode event")
 		return nil
 	}
 
@@ -0,0 +1,168 @@
+package api
+
+import (
+	"regexp"
+
+	s "github.com/akiver/cs-demo-analyzer/internal/strings"
+	"github.com/akiver/cs-demo-analyzer/pkg/api/constants"
+	events "github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/events"
+)
+
+// The server announces the live in the chat in english or portuguese, e.g. "LIVE! LIVE! LIVE!" or "VALENDO!".
+var gamersclubLiveMessageRegex = regexp.MustCompile(`(?i)\b(live|valendo)\b`)
+
+// Knife rounds are announced too, e.g. "KNIFE ROUND LIVE!" or "ROUND FACA!", they must not start the match.
+var gamersclubKnifeMessageRegex = regexp.MustCompile(`(?i)\b(knife|faca)\b`)
+
+// Live detection state, the match starts when the live message is received or at the end of the warmup if it has been
+// sent during it.
+type gamersclubLiveState struct {
+	liveRequested     bool // A live message has been received during the warmup
+	firstRoundStarted bool // Live messages are ignored once the first round of the match has started
+}
+
+// Returns true when the chat message starts the match.
+func (state *gamersclubLiveState) handleMessage(text string, roundCount int, isWarmupPeriod bool) bool {
+	// The server may print live messages again during the match, e.g. after a pause.
+	if state.firstRoundStarted || roundCount > 0 {
+		return false
+	}
+
+	text = s.RemoveInvisibleChars(text)
+	if !gamersclubLiveMessageRegex.MatchString(text) || gamersclubKnifeMessageRegex.MatchString(text) {
+		return false
+	}
+
+	if isWarmupPeriod {
+		state.liveRequested = true
+		return false
+	}
+
+	return true
+}
+
+// Gamersclub uses an eBot fork but the game rules match started prop changes several times during the warmup and the
+// knife round, the match is considered live only after the live message of the server.
+func createGamersclubAnalyzer(analyzer *Analyzer) {
+	match := analyzer.match
+	match.gameModeStr = constants.GameModeStrCompetitive
+	parser := analyzer.parser
+	matchStarted := false
+	liveState := &gamersclubLiveState{}
+	lastRoundEndTick := -1 // Used to detect backup restore
+
+	analyzer.matchStarted = func() bool {
+		return matchStarted
+	}
+
+	startMatch := func() {
+		analyzer.reset()
+		matchStarted = true
+		liveState.liveRequested = false
+		analyzer.registerUnknownPlayers()
+		analyzer.processMatchStart()
+	}
+
+	stopMatch := func() {
+		analyzer.reset()
+		matchStarted = false
+		liveState.firstRoundStarted = false
+	}
+
+	parser.RegisterEventHandler(func(event events.SayText) {
+		if liveState.handleMessage(event.Text, len(match.Rounds), parser.GameState().IsWarmupPeriod()) {
+			startMatch()
+		}
+	})
+
+	parser.RegisterEventHandler(func(event events.IsWarmupPeriodChanged) {
+		if event.NewIsWarmupPeriod {
+			// The match went back to the warmup before the end of the first round, e.g. a player left.
+			if matchStarted && len(match.Rounds) == 0 {
+				stopMatch()
+			}
+			return
+		}
+
+		if liveState.liveRequested {
+			startMatch()
+		}
+	})
+
+	parser.RegisterEventHandler(func(event events.RoundFreezetimeChanged) {
+		if !analyzer.matchStarted() {
+			return
+		}
+
+		analyzer.defaultRoundFreezetimeChangedHandler(event)
+		// The live message may be sent without a restart after it, the first round is then the current one.
+		if !event.NewIsFreezetime {
+			liveState.firstRoundStarted = true
+		}
+	})
+
+	parser.RegisterEventHandler(func(event events.RoundStart) {
+		isBackupRestoration := analyzer.currentTick() == lastRoundEndTick
+		if isBackupRestoration && len(match.Rounds) > 0 {
+			matchStarted = true
+			analyzer.resetCurrentRound()
+			currentRound := analyzer.currentRound
+			currentRound.StartFrame = parser.CurrentFrame()
+			currentRound.StartTick = analyzer.currentTick()
+			return
+		}
+
+		if !analyzer.matchStarted() || analyzer.currentTick() == 0 {
+			return
+		}
+
+		// The live message may be sent before the last restart of the game, the first round starts after it.
+		if len(match.Rounds) == 0 {
+			startMatch()
+			liveState.firstRoundStarted = true
+			return
+		}
+
+		analyzer.createRound()
+	})
+
+	parser.RegisterEventHandler(func(event events.RoundEnd) {
+		lastRoundEndTick = analyzer.currentTick()
+		if !analyzer.matchStarted() || len(match.Rounds) > 0 {
+			return
+		}
+
+		knifeKillCount := 0
+		killCount := 0
+		for _, kill := range match.Kills {
+			if kill.RoundNumber != analyzer.currentRound.Number {
+				continue
+			}
+			if kill.WeaponName == constants.WeaponKnife {
+				knifeKillCount++
+			}
+			killCount++
+		}
+
+		// The knife round has been played after the live message, the match will start after the next one.
+		isKnifeRound := killCount > 0 && killCount == knifeKillCount
+		if isKnifeRound {
+			stopMatch()
+		}
+	})
+
+	parser.RegisterEventHandler(func(event events.RoundEndOfficial) {
+		if !analyzer.matchStarted() {
+			return
+		}
+
+		isBackupRestoration := analyzer.currentTick() == lastRoundEndTick
+		if isBackupRestoration {
+			return
+		}
+
+		match.Rounds = append(match.Rounds, analyzer.currentRound)
+	})
+
+	parser.RegisterEventHandler(analyzer.defaultAnnouncementWinPanelMatchHandler)
+}
@@ -0,0 +1,27 @@
+package api
+
+import "testing"
+
+func TestGamersclubLiveState_HandleMessage(t *testing.T) {
+	state := &gamersclubLiveState{}
+
+	if state.handleMessage("KNIFE ROUND LIVE!", 0, false) {
+		t.Errorf("expected the knife round message not to start the match")
+	}
+	if state.handleMessage("LIVE! LIVE! LIVE!", 0, true) || !state.liveRequested {
+		t.Errorf("expected the warmup live message to be requested without starting the match")
+	}
+	if !state.handleMessage("\u200bVALENDO!", 0, false) {
+		t.Errorf("expected the live message to start the match")
+	}
+
+	// The first round has started, the live message sent after a pause must not restart the match.
+	state.firstRoundStarted = true
+	if state.handleMessage("LIVE! LIVE! LIVE!", 0, false) {
+		t.Errorf("expected the live message to be ignored once the first round has started")
+	}
+	state.firstRoundStarted = false
+	if state.handleMessage("LIVE! LIVE! LIVE!", 1, false) {
+		t.Errorf("expected the live message to be ignored once a round has been played")
+	}
+}
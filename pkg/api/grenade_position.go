@@ -1,7 +1,6 @@
 package api
 
 import (
-	"fmt"
 	"math"
 
 	"github.com/akiver/cs-demo-analyzer/pkg/api/constants"
@@ -41,16 +40,16 @@ type grenadeProjectilePositionSample struct {
 
 func newGrenadePositionFromProjectile(analyzer *Analyzer, projectile *common.GrenadeProjectile) *GrenadePosition {
 	if projectile.WeaponInstance == nil {
-		fmt.Println("Projectile weapon instance nil in grenade projectile position")
+		analyzer.warn(constants.WarningKindMissingEntity, "Projectile weapon instance nil in grenade projectile position")
 		return nil
 	}
 
 	thrower := projectile.Thrower
 	if thrower == nil {
-		fmt.Println("Thrower nil in grenade projectile position, falling back to owner")
+		analyzer.warn(constants.WarningKindMissingEntity, "Thrower nil in grenade projectile position, falling back to owner")
 		thrower = projectile.WeaponInstance.Owner
 		if thrower == nil {
-			fmt.Println("Owner nil in grenade projectile position")
+			analyzer.warn(constants.WarningKindMissingEntity, "Owner nil in grenade projectile position")
 			return nil
 		}
 	}
@@ -1,8 +1,7 @@
 package api
 
 import (
-	"fmt"
-
+	"github.com/akiver/cs-demo-analyzer/pkg/api/constants"
 	"github.com/golang/geo/r2"
 	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
 )
@@ -23,7 +22,7 @@ type InfernoPosition struct {
 func newInfernoPositionFromInferno(analyzer *Analyzer, inferno *common.Inferno) *InfernoPosition {
 	thrower := inferno.Thrower()
 	if thrower == nil {
-		fmt.Println("Thrower nil in inferno")
+		analyzer.warn(constants.WarningKindMissingEntity, "Thrower nil in inferno")
 		return nil
 	}
 
@@ -1,7 +1,6 @@
 package api
 
 import (
-	"fmt"
 	stdmath "math"
 
 	"github.com/akiver/cs-demo-analyzer/internal/math"
@@ -66,6 +65,7 @@ type Kill struct {
 	IsTradeKill              bool                 `json:"isTradeKill"`  // The attacker did a trade kill
 	IsTradeDeath             bool                 `json:"isTradeDeath"` // The victim did a trade death
 	Distance                 float32              `json:"distance"`
+	WeaponSkin               *WeaponSkin          `json:"weaponSkin"` // nil if the weapon cosmetic data is not available
 }
 
 func (kill *Kill) IsSuicide() bool {
@@ -78,11 +78,11 @@ func (kill *Kill) IsTeamKill() bool {
 
 func newKillFromGameEvent(analyzer *Analyzer, event events.Kill) *Kill {
 	if event.Weapon == nil {
-		fmt.Println("Player kill event without weapon occurred")
+		analyzer.warn(constants.WarningKindMissingEntity, "Player kill event without weapon occurred")
 		return nil
 	}
 	if event.Victim == nil {
-		fmt.Println("Player kill event without victim occurred")
+		analyzer.warn(constants.WarningKindMissingEntity, "Player kill event without victim occurred")
 		return nil
 	}
 	parser := analyzer.parser
@@ -121,7 +121,7 @@ func newKillFromGameEvent(analyzer *Analyzer, event events.Kill) *Kill {
 				isKillerRunning = true
 			}
 		} else {
-			fmt.Printf("Error: Weapon '%s' not found in accurate speed constants\n", wName)
+			analyzer.warn(constants.WarningKindUnknownWeapon, "Weapon %q not found in accurate speed constants", wName)
 		}
 	}
 
@@ -141,7 +141,7 @@ func newKillFromGameEvent(analyzer *Analyzer, event events.Kill) *Kill {
 	var isTradeKill bool
 	for _, kill := range analyzer.match.Kills {
 		if kill.RoundNumber == analyzer.currentRound.Number && killerSteamID != 0 {
-			if kill.KillerSteamID64 == event.Victim.SteamID64 && !analyzer.secondsHasPassedSinceTick(tradeKillDelaySeconds, kill.Tick) {
+			if kill.KillerSteamID64 == event.Victim.SteamID64 && !analyzer.secondsHasPassedSinceTick(analyzer.config.TradeKillDelaySeconds, kill.Tick) {
 				isTradeKill = true
 				kill.IsTradeDeath = true
 			}
@@ -204,6 +204,7 @@ func newKillFromGameEvent(analyzer *Analyzer, event events.Kill) *Kill {
 		PenetratedObjects:        event.PenetratedObjects,
 		WeaponName:               equipmentToWeaponName[event.Weapon.Type],
 		WeaponType:               getEquipmentWeaponType(*event.Weapon),
+		WeaponSkin:               newWeaponSkinFromEntity(event.Weapon.Entity, weaponEconItemPrefix),
 		IsKillerControllingBot:   isKillerControllingBot,
 		IsVictimControllingBot:   event.Victim.IsControllingBot(),
 		IsAssisterControllingBot: isAssisterControllingBot,
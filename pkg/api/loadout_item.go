@@ -0,0 +1,108 @@
+package api
+
+import (
+	"sort"
+
+	"github.com/akiver/cs-demo-analyzer/pkg/api/constants"
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
+)
+
+type LoadoutItem struct {
+	Frame           int                       `json:"frame"`
+	Tick            int                       `json:"tick"`
+	RoundNumber     int                       `json:"roundNumber"`
+	PlayerName      string                    `json:"playerName"`
+	PlayerSteamID64 uint64                    `json:"playerSteamId"`
+	PlayerSide      common.Team               `json:"playerSide"`
+	Type            constants.LoadoutItemType `json:"type"`
+	WeaponName      constants.WeaponName      `json:"weaponName"` // Weapons only
+	// Item definition index for weapons and gloves, music kit id for music kits.
+	ItemDefinitionIndex int `json:"itemDefinitionIndex"`
+	// Agents only, hash of the player model resource. Agents can't be named without the game files but the same hash
+	// means the same agent.
+	ModelHash uint64      `json:"modelHash"`
+	Skin      *WeaponSkin `json:"skin"` // Weapons and gloves only
+}
+
+func newLoadoutItem(analyzer *Analyzer, player *common.Player, itemType constants.LoadoutItemType) *LoadoutItem {
+	return &LoadoutItem{
+		Frame:           analyzer.parser.CurrentFrame(),
+		Tick:            analyzer.currentTick(),
+		RoundNumber:     analyzer.currentRound.Number,
+		PlayerName:      player.Name,
+		PlayerSteamID64: player.SteamID64,
+		PlayerSide:      player.Team,
+		Type:            itemType,
+	}
+}
+
+func newPlayerLoadout(analyzer *Analyzer, player *common.Player) []*LoadoutItem {
+	var items []*LoadoutItem
+
+	weapons := player.Weapons()
+	sort.Slice(weapons, func(i int, j int) bool {
+		return weapons[i].Type < weapons[j].Type
+	})
+	for _, weapon := range weapons {
+		weaponType := getEquipmentWeaponType(*weapon)
+		if weaponType == constants.WeaponTypeGrenade || weaponType == constants.WeaponTypeEquipment {
+			continue
+		}
+		skin := newWeaponSkinFromEntity(weapon.Entity, weaponEconItemPrefix)
+		if skin == nil {
+			continue
+		}
+		item := newLoadoutItem(analyzer, player, constants.LoadoutItemTypeWeapon)
+		item.WeaponName = equipmentToWeaponName[weapon.Type]
+		item.ItemDefinitionIndex = skin.ItemDefinitionIndex
+		item.Skin = skin
+		items = append(items, item)
+	}
+
+	if pawn := player.PlayerPawnEntity(); pawn != nil {
+		if gloves := newWeaponSkinFromEntity(pawn, glovesEconItemPrefix); gloves != nil && gloves.ItemDefinitionIndex != 0 {
+			item := newLoadoutItem(analyzer, player, constants.LoadoutItemTypeGloves)
+			item.ItemDefinitionIndex = gloves.ItemDefinitionIndex
+			item.Skin = gloves
+			items = append(items, item)
+		}
+		if model, exists := getEntityPropertyValue(pawn, "CBodyComponent.m_hModel"); exists {
+			if modelHash, isUint64 := model.Any.(uint64); isUint64 && modelHash != 0 {
+				item := newLoadoutItem(analyzer, player, constants.LoadoutItemTypeAgent)
+				item.ModelHash = modelHash
+				items = append(items, item)
+			}
+		}
+	}
+
+	if player.Entity != nil {
+		if musicKit, exists := getEntityPropertyValue(player.Entity, "m_iMusicKitID"); exists && propertyValueToInt(musicKit) != 0 {
+			item := newLoadoutItem(analyzer, player, constants.LoadoutItemTypeMusicKit)
+			item.ItemDefinitionIndex = propertyValueToInt(musicKit)
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// Capture players loadout once per round at the end of the freeze time, when players have bought their weapons.
+func (analyzer *Analyzer) captureLoadouts() {
+	round := analyzer.currentRound
+	if analyzer.isLoadoutCaptured || round.FreezeTimeEndTick == -1 || round.EndTick > 0 {
+		return
+	}
+
+	match := analyzer.match
+	players := analyzer.parser.GameState().Participants().Playing()
+	sort.Slice(players, func(i int, j int) bool {
+		return players[i].SteamID64 < players[j].SteamID64
+	})
+	for _, player := range players {
+		if player.IsBot || player.SteamID64 == 0 {
+			continue
+		}
+		match.LoadoutItems = append(match.LoadoutItems, newPlayerLoadout(analyzer, player)...)
+	}
+	analyzer.isLoadoutCaptured = true
+}
@@ -2,7 +2,6 @@ package api
 
 import (
 	"encoding/json"
-	"fmt"
 	"sort"
 	"time"
 
@@ -11,6 +10,7 @@ import (
 	"github.com/akiver/cs-demo-analyzer/internal/slice"
 	"github.com/akiver/cs-demo-analyzer/pkg/api/constants"
 	"github.com/akiver/cs-demo-analyzer/pkg/api/funData"
+	"github.com/akiver/cs-demo-analyzer/pkg/api/plugin"
 	"github.com/golang/geo/r3"
 	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
 )
@@ -42,6 +42,8 @@ type Match struct {
 	MaxRounds                 int                         `json:"maxRounds"` // mp_maxrounds if detected or based on final scores
 	OvertimeCount             int                         `json:"overtimeCount"`
 	HasVacLiveBan             bool                        `json:"hasVacLiveBan"`
+	EndedBySurrender          bool                        `json:"endedBySurrender"`
+	ValveMatchInfo            *ValveMatchInfo             `json:"valveMatchInfo"` // Valve demos only, the .info file must be next to the .dem file
 	TeamA                     *Team                       `json:"teamA"` // Team A is the Team that started as CT
 	TeamB                     *Team                       `json:"teamB"` // Team B is the Team that started as T
 	Winner                    *Team                       `json:"winner"`
@@ -79,6 +81,20 @@ type Match struct {
 	ChatMessages              []*ChatMessage              `json:"chatMessages"`
 	Footsteps                 []*Footstep                 `json:"footsteps"`
 	AwpHoldDeaths             []*AwpHoldDeath             `json:"awpHoldDeaths"`
+	MovementEvents            []*MovementEvent            `json:"movementEvents"`
+	PlayerRoundMovements      []*PlayerRoundMovement      `json:"playerRoundMovements"`
+	TeamSetups                []*TeamSetup                `json:"teamSetups"`
+	BombEvents                []*BombEvent                `json:"bombEvents"`
+	Timeouts                  []*Timeout                  `json:"timeouts"`
+	PlayerSessionEvents       []*PlayerSessionEvent       `json:"playerSessionEvents"`
+	Votes                     []*Vote                     `json:"votes"`
+	VoiceActivities           []*VoiceActivity            `json:"voiceActivities"`
+	LoadoutItems              []*LoadoutItem              `json:"loadoutItems"`
+	Warnings                  []*AnalysisWarning          `json:"warnings"` // Not cleared on match restarts, they describe the demo data quality
+	Validation                *ValidationReport           `json:"validation"`
+	AnalyzerConfig            *AnalyzerConfig             `json:"analyzerConfig"` // Heuristics thresholds used during the analysis
+	CustomTables              []*plugin.Table             `json:"customTables"`   // Tables created by plugins
+	Metrics                   []*MetricTable              `json:"metrics"`        // Computed from AnalyzeDemoOptions.Metrics
 	scoreTeamA                *int
 	scoreTeamB                *int
 	lastPlayersPosition       map[uint64]r3.Vector
@@ -88,20 +104,31 @@ type Match struct {
 	hasCounterStrafeRoundIndexes bool
 	counterStrafeShotsByRoundPlayer map[roundPlayerKey][]*Shot
 	counterStrafeButtonsByRoundPlayer map[roundPlayerKey][]*funData.PlayerButtons
+	playerRoundMovementsByKey map[roundPlayerKey]*PlayerRoundMovement
+	playerRoundsPlayed        map[roundPlayerKey]bool
+	// Max number of players, bots included, connected on a team at the same time while the round was live.
+	connectedPlayerCountByRound map[int]int
+	voiceWriters              *voiceOggWriters // Set only when exporting voice, see AnalyzeAndExportDemoOptions.VoiceOutputFolder
+	// Timeout types that have a game rules prop in the demo, chat requests of these types are not exported alone.
+	gameRulesTimeoutTypes map[constants.TimeoutType]bool
 }
 
 type MatchAlias Match
 
 type MatchJSON struct {
 	*MatchAlias
-	GameModeStr string `json:"gameModeStr"`
+	GameModeStr   string          `json:"gameModeStr"`
+	SetupClusters []*SetupCluster       `json:"setupClusters"`
+	PlayerRoles   []*PlayerRoleEvidence `json:"playerRoles"`
 }
 
 func (match *Match) MarshalJSON() ([]byte, error) {
 
 	return json.Marshal(MatchJSON{
 		MatchAlias:  (*MatchAlias)(match),
-		GameModeStr: match.GameModeStr().String(),
+		GameModeStr:   match.GameModeStr().String(),
+		SetupClusters: match.SetupClusters(),
+		PlayerRoles:   match.PlayerRoles(),
 	})
 }
 
@@ -115,8 +142,6 @@ func (match Match) GameModeStr() constants.GameModeStr {
 		return gameModeStr
 	}
 
-	fmt.Printf("Unknown game mode string for game type: %d and game mode: %d", match.GameType, match.GameMode)
-
 	return constants.GameModeStrCompetitive
 }
 
@@ -236,6 +261,28 @@ func (match *Match) ensureCounterStrafeRoundIndexes() {
 	match.hasCounterStrafeRoundIndexes = true
 }
 
+// This returns the side and the team name of the player during the given round.
+func (match *Match) playerSideAtRound(steamID64 uint64, roundNumber int) (common.Team, string) {
+	player := match.PlayersBySteamID[steamID64]
+	if player == nil || player.Team == nil {
+		return common.TeamUnassigned, ""
+	}
+
+	for _, round := range match.Rounds {
+		if round.Number != roundNumber {
+			continue
+		}
+		if player.Team.Name == round.TeamAName {
+			return round.TeamASide, round.TeamAName
+		}
+		if player.Team.Name == round.TeamBName {
+			return round.TeamBSide, round.TeamBName
+		}
+	}
+
+	return common.TeamUnassigned, player.Team.Name
+}
+
 func (match *Match) GetPlayerEconomyAtRound(playerName string, steamID64 uint64, roundNumber int) *PlayerEconomy {
 	for _, economy := range match.PlayerEconomies {
 		if economy.RoundNumber == roundNumber && economy.SteamID64 == steamID64 && economy.Name == playerName {
@@ -329,23 +376,27 @@ func (match *Match) initTeams() {
 	match.scoreTeamB = &teamB.Score
 }
 
-func newMatch(source constants.DemoSource, demoInfo *demo.Demo) Match {
-	game := constants.CSGO
-	if demoInfo.IsSource2() {
-		// The build number of CS2 when it was publicly available is 9832, everything below is coming from the limited test.
-		if demoInfo.BuildNumber < 9832 {
-			game = constants.CS2LT
-		} else {
-			game = constants.CS2
-		}
+func getDemoGame(demoInfo *demo.Demo) constants.Game {
+	if !demoInfo.IsSource2() {
+		return constants.CSGO
 	}
 
+	// The build number of CS2 when it was publicly available is 9832, everything below is coming from the limited test.
+	if demoInfo.BuildNumber < 9832 {
+		return constants.CS2LT
+	}
+
+	return constants.CS2
+}
+
+func newMatch(source constants.DemoSource, demoInfo *demo.Demo) Match {
 	match := Match{
 		Checksum:                  demoInfo.Checksum,
 		Source:                    source,
-		Game:                      game,
+		Game:                      getDemoGame(demoInfo),
 		Type:                      "GOTV", // By default assume it's a GOTV demo, it will be updated during parsing.
 		ShareCode:                 demoInfo.ShareCode,
+		ValveMatchInfo:            newValveMatchInfo(demoInfo.MatchInfo),
 		TickCount:                 demoInfo.TickCount,
 		Date:                      demoInfo.Date,
 		DemoFilePath:              demoInfo.FilePath,
@@ -392,6 +443,18 @@ func newMatch(source constants.DemoSource, demoInfo *demo.Demo) Match {
 		PlayerButtons:             []*funData.PlayerButtons{},
 		Footsteps:                 []*Footstep{},
 		AwpHoldDeaths:             []*AwpHoldDeath{},
+		MovementEvents:            []*MovementEvent{},
+		PlayerRoundMovements:      []*PlayerRoundMovement{},
+		TeamSetups:                []*TeamSetup{},
+		BombEvents:                []*BombEvent{},
+		Timeouts:                  []*Timeout{},
+		PlayerSessionEvents:       []*PlayerSessionEvent{},
+		Votes:                     []*Vote{},
+		VoiceActivities:           []*VoiceActivity{},
+		LoadoutItems:              []*LoadoutItem{},
+		Warnings:                  []*AnalysisWarning{},
+		CustomTables:              []*plugin.Table{},
+		Metrics:                   []*MetricTable{},
 		lastPlayersPosition:       make(map[uint64]r3.Vector),
 		prevPlayersPosition:       make(map[uint64]r3.Vector),
 		lastPlayersTick:           make(map[uint64]int),
@@ -399,6 +462,10 @@ func newMatch(source constants.DemoSource, demoInfo *demo.Demo) Match {
 		hasCounterStrafeRoundIndexes: false,
 		counterStrafeShotsByRoundPlayer: make(map[roundPlayerKey][]*Shot),
 		counterStrafeButtonsByRoundPlayer: make(map[roundPlayerKey][]*funData.PlayerButtons),
+		playerRoundMovementsByKey: make(map[roundPlayerKey]*PlayerRoundMovement),
+		playerRoundsPlayed:        make(map[roundPlayerKey]bool),
+		connectedPlayerCountByRound: make(map[int]int),
+		gameRulesTimeoutTypes:     make(map[constants.TimeoutType]bool),
 	}
 
 	match.initTeams()
@@ -439,8 +506,22 @@ func (match *Match) reset() {
 	match.GrenadeProjectilesDestroy = []*GrenadeProjectileDestroy{}
 	match.PlayerEconomies = []*PlayerEconomy{}
 	match.PlayerButtons = []*funData.PlayerButtons{}
+	for _, table := range match.CustomTables {
+		table.Rows = []*plugin.Row{}
+	}
 	match.Footsteps = []*Footstep{}
 	match.AwpHoldDeaths = []*AwpHoldDeath{}
+	match.MovementEvents = []*MovementEvent{}
+	match.PlayerRoundMovements = []*PlayerRoundMovement{}
+	match.TeamSetups = []*TeamSetup{}
+	match.BombEvents = []*BombEvent{}
+	match.Timeouts = []*Timeout{}
+	match.PlayerSessionEvents = []*PlayerSessionEvent{}
+	match.Votes = []*Vote{}
+	match.VoiceActivities = []*VoiceActivity{}
+	match.LoadoutItems = []*LoadoutItem{}
+	match.voiceWriters.discardAll()
+	match.EndedBySurrender = false
 	match.lastPlayersPosition = make(map[uint64]r3.Vector)
 	match.prevPlayersPosition = make(map[uint64]r3.Vector)
 	match.lastPlayersTick = make(map[uint64]int)
@@ -448,6 +529,9 @@ func (match *Match) reset() {
 	match.hasCounterStrafeRoundIndexes = false
 	match.counterStrafeShotsByRoundPlayer = make(map[roundPlayerKey][]*Shot)
 	match.counterStrafeButtonsByRoundPlayer = make(map[roundPlayerKey][]*funData.PlayerButtons)
+	match.playerRoundMovementsByKey = make(map[roundPlayerKey]*PlayerRoundMovement)
+	match.playerRoundsPlayed = make(map[roundPlayerKey]bool)
+	match.connectedPlayerCountByRound = make(map[int]int)
 	match.initTeams()
 }
 
@@ -542,12 +626,53 @@ func (match *Match) resetRound(roundNumber int) {
 	match.PlayerButtons = slice.Filter(match.PlayerButtons, func(event *funData.PlayerButtons, index int) bool {
 		return event.RoundNumber != roundNumber
 	})
+	for _, table := range match.CustomTables {
+		table.Rows = slice.Filter(table.Rows, func(row *plugin.Row, index int) bool {
+			return row.RoundNumber != roundNumber
+		})
+	}
 	match.Footsteps = slice.Filter(match.Footsteps, func(event *Footstep, index int) bool {
 		return event.RoundNumber != roundNumber
 	})
 	match.AwpHoldDeaths = slice.Filter(match.AwpHoldDeaths, func(event *AwpHoldDeath, index int) bool {
 		return event.RoundNumber != roundNumber
 	})
+	match.MovementEvents = slice.Filter(match.MovementEvents, func(event *MovementEvent, index int) bool {
+		return event.RoundNumber != roundNumber
+	})
+	match.PlayerRoundMovements = slice.Filter(match.PlayerRoundMovements, func(movement *PlayerRoundMovement, index int) bool {
+		return movement.RoundNumber != roundNumber
+	})
+	match.TeamSetups = slice.Filter(match.TeamSetups, func(setup *TeamSetup, index int) bool {
+		return setup.RoundNumber != roundNumber
+	})
+	match.BombEvents = slice.Filter(match.BombEvents, func(event *BombEvent, index int) bool {
+		return event.RoundNumber != roundNumber
+	})
+	match.Timeouts = slice.Filter(match.Timeouts, func(timeout *Timeout, index int) bool {
+		return timeout.RoundNumber != roundNumber
+	})
+	match.Votes = slice.Filter(match.Votes, func(vote *Vote, index int) bool {
+		return vote.RoundNumber != roundNumber
+	})
+	match.VoiceActivities = slice.Filter(match.VoiceActivities, func(activity *VoiceActivity, index int) bool {
+		return activity.RoundNumber != roundNumber
+	})
+	match.LoadoutItems = slice.Filter(match.LoadoutItems, func(item *LoadoutItem, index int) bool {
+		return item.RoundNumber != roundNumber
+	})
+	for key := range match.playerRoundMovementsByKey {
+		if key.roundNumber == roundNumber {
+			delete(match.playerRoundMovementsByKey, key)
+		}
+	}
+	for key := range match.playerRoundsPlayed {
+		if key.roundNumber == roundNumber {
+			delete(match.playerRoundsPlayed, key)
+		}
+	}
+	delete(match.connectedPlayerCountByRound, roundNumber)
+	match.voiceWriters.discardRound(roundNumber)
 }
 
 func (match *Match) deleteIncompleteRounds() {
@@ -0,0 +1,324 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/akiver/cs-demo-analyzer/internal/converters"
+	"github.com/akiver/cs-demo-analyzer/internal/expr"
+	"github.com/akiver/cs-demo-analyzer/internal/slice"
+	"github.com/akiver/cs-demo-analyzer/pkg/api/constants"
+	"gopkg.in/yaml.v3"
+)
+
+// Derived metric computed from a Match collection once the analysis is done, expressions syntax is described in
+// the README.
+type MetricDefinition struct {
+	// Used as the table file name suffix and as the players column name, lowercase letters, digits and underscores.
+	Name string `json:"name" yaml:"name"`
+	// JSON name of a Match array, e.g. kills, damages, shots.
+	Collection string `json:"collection" yaml:"collection"`
+	// Bool expression, all rows of the collection are used if empty.
+	Filter string `json:"filter" yaml:"filter"`
+	// Expressions whose values identify a group, there is a single group if empty.
+	GroupBy []string `json:"groupBy" yaml:"groupBy"`
+	// Defaults to count.
+	Aggregate constants.MetricAggregate `json:"aggregate" yaml:"aggregate"`
+	// Number expression aggregated, required unless the aggregate is count.
+	Field string `json:"field" yaml:"field"`
+	// Expression returning a player SteamID, the metric is grouped by player and added as a column of _players.csv.
+	// It can't be used with GroupBy.
+	PlayerField string `json:"playerField" yaml:"playerField"`
+}
+
+type MetricRow struct {
+	Keys  []string `json:"keys"` // Values of the group by expressions, in the same order
+	Value float64  `json:"value"`
+}
+
+type MetricTable struct {
+	Name      string                    `json:"name"`
+	Aggregate constants.MetricAggregate `json:"aggregate"`
+	Columns   []string                  `json:"columns"` // Group by expressions followed by the aggregate
+	Rows      []*MetricRow              `json:"rows"`
+	// The only key of the rows is a player SteamID.
+	IsPlayerMetric bool `json:"isPlayerMetric"`
+}
+
+type metricsFile struct {
+	Metrics []MetricDefinition `json:"metrics" yaml:"metrics"`
+}
+
+type compiledMetric struct {
+	definition MetricDefinition
+	// Index of the collection field in Match.
+	collectionIndex int
+	filter          *expr.Expression
+	groupBy         []*expr.Expression
+	field           *expr.Expression
+}
+
+// Match arrays of structs that can be queried, by JSON name.
+func metricCollectionIndex(name string) (int, error) {
+	matchType := reflect.TypeOf(Match{})
+	names := []string{}
+	for index := range matchType.NumField() {
+		field := matchType.Field(index)
+		if !field.IsExported() || field.Type.Kind() != reflect.Slice || field.Type.Elem().Kind() != reflect.Pointer ||
+			field.Type.Elem().Elem().Kind() != reflect.Struct {
+			continue
+		}
+
+		jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		// Computed after the analysis or generic tables.
+		if jsonName == "metrics" || jsonName == "customTables" {
+			continue
+		}
+		if jsonName == name {
+			return index, nil
+		}
+		names = append(names, jsonName)
+	}
+
+	return -1, fmt.Errorf("unknown collection %q, valid values: [%s]", name, strings.Join(names, ","))
+}
+
+func compileMetric(definition MetricDefinition) (*compiledMetric, error) {
+	if !tableNameRegexp.MatchString(definition.Name) {
+		return nil, fmt.Errorf("invalid metric name %q", definition.Name)
+	}
+
+	metric := &compiledMetric{definition: definition}
+	if metric.definition.Aggregate == "" {
+		metric.definition.Aggregate = constants.MetricAggregateCount
+	}
+	if !slice.Contains(constants.MetricAggregates, metric.definition.Aggregate) {
+		return nil, fmt.Errorf("metric %s: invalid aggregate %q, valid values: [count,sum,avg,min,max]", definition.Name, definition.Aggregate)
+	}
+
+	var err error
+	metric.collectionIndex, err = metricCollectionIndex(definition.Collection)
+	if err != nil {
+		return nil, fmt.Errorf("metric %s: %v", definition.Name, err)
+	}
+	elementType := reflect.TypeOf(Match{}).Field(metric.collectionIndex).Type.Elem()
+
+	parse := func(property string, source string, kind *expr.Kind) (*expr.Expression, error) {
+		expression, err := expr.Parse(source, elementType)
+		if err != nil {
+			return nil, fmt.Errorf("metric %s: invalid %s %q: %v", definition.Name, property, source, err)
+		}
+		if kind != nil && expression.Kind() != *kind {
+			return nil, fmt.Errorf("metric %s: %s %q must be a %s", definition.Name, property, source, *kind)
+		}
+
+		return expression, nil
+	}
+
+	if definition.Filter != "" {
+		kind := expr.KindBool
+		if metric.filter, err = parse("filter", definition.Filter, &kind); err != nil {
+			return nil, err
+		}
+	}
+
+	if metric.definition.Aggregate != constants.MetricAggregateCount {
+		kind := expr.KindNumber
+		if definition.Field == "" {
+			return nil, fmt.Errorf("metric %s: field is required with the %s aggregate", definition.Name, metric.definition.Aggregate)
+		}
+		if metric.field, err = parse("field", definition.Field, &kind); err != nil {
+			return nil, err
+		}
+	}
+
+	groupBy := definition.GroupBy
+	if definition.PlayerField != "" {
+		if len(groupBy) > 0 {
+			return nil, fmt.Errorf("metric %s: playerField can't be used with groupBy", definition.Name)
+		}
+		groupBy = []string{definition.PlayerField}
+	}
+	for _, source := range groupBy {
+		var kind *expr.Kind
+		property := "groupBy"
+		if definition.PlayerField != "" {
+			number := expr.KindNumber
+			kind = &number
+			property = "playerField"
+		}
+		expression, err := parse(property, source, kind)
+		if err != nil {
+			return nil, err
+		}
+		metric.groupBy = append(metric.groupBy, expression)
+	}
+
+	return metric, nil
+}
+
+func compileMetrics(definitions []MetricDefinition) ([]*compiledMetric, error) {
+	metrics := []*compiledMetric{}
+	names := make(map[string]bool)
+	for _, definition := range definitions {
+		if names[definition.Name] {
+			return nil, newError(ErrorCodeInvalidArgument, fmt.Sprintf("metric %s defined multiple times", definition.Name), nil)
+		}
+		names[definition.Name] = true
+
+		metric, err := compileMetric(definition)
+		if err != nil {
+			return nil, newError(ErrorCodeInvalidArgument, "invalid metric definition", err)
+		}
+		metrics = append(metrics, metric)
+	}
+
+	return metrics, nil
+}
+
+func ValidateMetricDefinitions(definitions []MetricDefinition) error {
+	_, err := compileMetrics(definitions)
+	return err
+}
+
+// Loads metrics definitions from a YAML or JSON (.json extension) file with a root "metrics" array.
+func LoadMetricDefinitions(metricsPath string) ([]MetricDefinition, error) {
+	data, err := os.ReadFile(metricsPath)
+	if err != nil {
+		return nil, newError(ErrorCodeInvalidArgument, fmt.Sprintf("unable to read metrics file %q", metricsPath), err)
+	}
+
+	var file metricsFile
+	if strings.EqualFold(filepath.Ext(metricsPath), ".json") {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&file)
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&file)
+		// Empty file.
+		if errors.Is(err, io.EOF) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return nil, newError(ErrorCodeInvalidArgument, fmt.Sprintf("invalid metrics file %q", metricsPath), err)
+	}
+
+	return file.Metrics, ValidateMetricDefinitions(file.Metrics)
+}
+
+type metricGroup struct {
+	row   *MetricRow
+	count int
+}
+
+func (metric *compiledMetric) compute(match *Match) *MetricTable {
+	definition := metric.definition
+	table := &MetricTable{
+		Name:           definition.Name,
+		Aggregate:      definition.Aggregate,
+		Rows:           []*MetricRow{},
+		IsPlayerMetric: definition.PlayerField != "",
+	}
+	for _, expression := range metric.groupBy {
+		table.Columns = append(table.Columns, expression.String())
+	}
+	table.Columns = append(table.Columns, definition.Aggregate.String())
+
+	groups := make(map[string]*metricGroup)
+	collection := reflect.ValueOf(match).Elem().Field(metric.collectionIndex)
+	for index := range collection.Len() {
+		element := collection.Index(index).Interface()
+		if metric.filter != nil && !metric.filter.Eval(element).Boolean {
+			continue
+		}
+
+		keys := make([]string, len(metric.groupBy))
+		for keyIndex, expression := range metric.groupBy {
+			keys[keyIndex] = expression.Eval(element).Text()
+		}
+		groupKey := strings.Join(keys, "\x00")
+		group, exists := groups[groupKey]
+		if !exists {
+			group = &metricGroup{row: &MetricRow{Keys: keys}}
+			groups[groupKey] = group
+			table.Rows = append(table.Rows, group.row)
+		}
+
+		group.count++
+		if metric.field == nil {
+			group.row.Value++
+			continue
+		}
+
+		value := metric.field.Eval(element).Number()
+		switch {
+		case definition.Aggregate == constants.MetricAggregateMin && group.count > 1:
+			group.row.Value = math.Min(group.row.Value, value)
+		case definition.Aggregate == constants.MetricAggregateMax && group.count > 1:
+			group.row.Value = math.Max(group.row.Value, value)
+		case definition.Aggregate == constants.MetricAggregateMin || definition.Aggregate == constants.MetricAggregateMax:
+			group.row.Value = value
+		default:
+			group.row.Value += value
+		}
+	}
+
+	if definition.Aggregate == constants.MetricAggregateAvg {
+		for _, group := range groups {
+			group.row.Value /= float64(group.count)
+		}
+	}
+
+	return table
+}
+
+// Computes metrics from an analyzed match, tables are in the same order as the definitions.
+func ComputeMetrics(match *Match, definitions []MetricDefinition) ([]*MetricTable, error) {
+	metrics, err := compileMetrics(definitions)
+	if err != nil {
+		return nil, err
+	}
+
+	tables := []*MetricTable{}
+	for _, metric := range metrics {
+		tables = append(tables, metric.compute(match))
+	}
+
+	return tables, nil
+}
+
+// Count values are integers.
+func (table *MetricTable) formatValue(value float64) string {
+	if table.Aggregate == constants.MetricAggregateCount {
+		return converters.IntToString(int(value))
+	}
+
+	return converters.Float64ToString(value)
+}
+
+// Players without rows have a 0 count or sum, other aggregates are empty.
+func (table *MetricTable) playerValueText(steamID64 uint64) string {
+	key := converters.Uint64ToString(steamID64)
+	for _, row := range table.Rows {
+		if row.Keys[0] == key {
+			return table.formatValue(row.Value)
+		}
+	}
+
+	if table.Aggregate == constants.MetricAggregateCount || table.Aggregate == constants.MetricAggregateSum {
+		return table.formatValue(0)
+	}
+
+	return ""
+}
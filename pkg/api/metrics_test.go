@@ -0,0 +1,145 @@
+package api
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/akiver/cs-demo-analyzer/pkg/api/constants"
+)
+
+func newMetricsTestMatch() *Match {
+	return &Match{
+		Kills: []*Kill{
+			{KillerSteamID64: 1, WeaponName: constants.WeaponAWP, Distance: 2500, IsHeadshot: true},
+			{KillerSteamID64: 1, WeaponName: constants.WeaponAWP, Distance: 1500},
+			{KillerSteamID64: 1, WeaponName: constants.WeaponAK47, Distance: 3000},
+			{KillerSteamID64: 2, WeaponName: constants.WeaponAWP, Distance: 2100},
+			{KillerSteamID64: 2, WeaponName: constants.WeaponAWP, Distance: 3100},
+		},
+	}
+}
+
+func TestComputeMetrics(t *testing.T) {
+	tables, err := ComputeMetrics(newMetricsTestMatch(), []MetricDefinition{
+		{
+			Name:        "long_awp_kills",
+			Collection:  "kills",
+			Filter:      `Distance > 2000 && WeaponName == "AWP"`,
+			PlayerField: "KillerSteamID64",
+		},
+		{
+			Name:       "avg_distance",
+			Collection: "kills",
+			GroupBy:    []string{"weaponName", "isHeadshot"},
+			Aggregate:  constants.MetricAggregateAvg,
+			Field:      "distance",
+		},
+		{
+			Name:       "max_distance",
+			Collection: "kills",
+			Aggregate:  constants.MetricAggregateMax,
+			Field:      "Distance / 100",
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected no error got %v", err)
+	}
+
+	longAwpKills := tables[0]
+	if !longAwpKills.IsPlayerMetric {
+		t.Errorf("expected a player metric")
+	}
+	for steamID64, expected := range map[uint64]string{1: "1", 2: "2", 3: "0"} {
+		if value := longAwpKills.playerValueText(steamID64); value != expected {
+			t.Errorf("expected %s long AWP kills for player %d got %s", expected, steamID64, value)
+		}
+	}
+
+	avgDistance := tables[1]
+	expectedColumns := []string{"weaponName", "isHeadshot", "avg"}
+	if len(avgDistance.Columns) != len(expectedColumns) {
+		t.Fatalf("expected columns %v got %v", expectedColumns, avgDistance.Columns)
+	}
+	expectedRows := []MetricRow{
+		{Keys: []string{"AWP", "1"}, Value: 2500},
+		{Keys: []string{"AWP", "0"}, Value: 2233.333333},
+		{Keys: []string{"AK-47", "0"}, Value: 3000},
+	}
+	if len(avgDistance.Rows) != len(expectedRows) {
+		t.Fatalf("expected %d rows got %d", len(expectedRows), len(avgDistance.Rows))
+	}
+	for index, expected := range expectedRows {
+		row := avgDistance.Rows[index]
+		if row.Keys[0] != expected.Keys[0] || row.Keys[1] != expected.Keys[1] || row.Value-expected.Value > 0.001 || expected.Value-row.Value > 0.001 {
+			t.Errorf("expected row %v got %v", expected, *row)
+		}
+	}
+	if value := avgDistance.playerValueText(1); value != "" {
+		t.Errorf("expected an empty avg without rows got %s", value)
+	}
+
+	maxDistance := tables[2]
+	if len(maxDistance.Rows) != 1 || maxDistance.Rows[0].Value != 31 {
+		t.Errorf("expected a single row with the max distance 31 got %v", maxDistance.Rows)
+	}
+}
+
+func TestValidateMetricDefinitions(t *testing.T) {
+	samples := []struct {
+		name       string
+		definition MetricDefinition
+	}{
+		{"invalid name", MetricDefinition{Name: "Long kills", Collection: "kills"}},
+		{"unknown collection", MetricDefinition{Name: "kills", Collection: "frags"}},
+		{"invalid aggregate", MetricDefinition{Name: "kills", Collection: "kills", Aggregate: "median", Field: "Distance"}},
+		{"missing field", MetricDefinition{Name: "kills", Collection: "kills", Aggregate: constants.MetricAggregateSum}},
+		{"string field", MetricDefinition{Name: "kills", Collection: "kills", Aggregate: constants.MetricAggregateSum, Field: "WeaponName"}},
+		{"number filter", MetricDefinition{Name: "kills", Collection: "kills", Filter: "Distance"}},
+		{"player field with group by", MetricDefinition{Name: "kills", Collection: "kills", PlayerField: "KillerSteamID64", GroupBy: []string{"WeaponName"}}},
+		{"string player field", MetricDefinition{Name: "kills", Collection: "kills", PlayerField: "KillerName"}},
+	}
+
+	for _, sample := range samples {
+		t.Run(sample.name, func(t *testing.T) {
+			err := ValidateMetricDefinitions([]MetricDefinition{sample.definition})
+			if !errors.Is(err, ErrInvalidArgument) {
+				t.Errorf("expected an invalid argument error got %v", err)
+			}
+		})
+	}
+
+	duplicated := MetricDefinition{Name: "kills", Collection: "kills"}
+	if err := ValidateMetricDefinitions([]MetricDefinition{duplicated, duplicated}); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("expected an invalid argument error for duplicated metrics got %v", err)
+	}
+}
+
+func TestLoadMetricDefinitions(t *testing.T) {
+	metricsPath := filepath.Join(t.TempDir(), "metrics.yaml")
+	content := `metrics:
+  - name: awp_kills
+    collection: kills
+    filter: WeaponName == 'AWP'
+    playerField: killerSteamId
+`
+	if err := os.WriteFile(metricsPath, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	definitions, err := LoadMetricDefinitions(metricsPath)
+	if err != nil {
+		t.Fatalf("expected no error got %v", err)
+	}
+	if len(definitions) != 1 || definitions[0].Name != "awp_kills" || definitions[0].PlayerField != "killerSteamId" {
+		t.Errorf("unexpected definitions %+v", definitions)
+	}
+
+	if err := os.WriteFile(metricsPath, []byte("metrics:\n  - name: kills\n    colection: kills\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadMetricDefinitions(metricsPath); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("expected an invalid argument error for unknown keys got %v", err)
+	}
+}
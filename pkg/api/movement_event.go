@@ -0,0 +1,447 @@
+package api
+
+import (
+	"sort"
+
+	"github.com/akiver/cs-demo-analyzer/pkg/api/constants"
+	"github.com/akiver/cs-demo-analyzer/pkg/api/funData"
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
+)
+
+const (
+	// A standing jump keeps the player airborne for ~0.75s, jump presses made sooner are scroll spam while in the air.
+	movementJumpMinIntervalSeconds = 0.5
+	// A new jump pressed within this delay after the previous one is a bhop, i.e. the player jumped right after landing.
+	movementJumpMaxIntervalSeconds = 0.85
+	// Delay between a jump press and a shot to consider the shot as a jump-shot.
+	movementJumpShotWindowSeconds = 0.85
+	movementBhopMinJumpCount      = 3
+	// Max delay between 2 opposite strafe keys to be part of the same jiggle peek.
+	movementJiggleMaxReversalIntervalSeconds = 0.4
+	movementJiggleMinReversalCount           = 3
+	// A wide swing is a strafe held in the same direction at least this long, i.e. longer than a jiggle peek direction.
+	movementWideSwingMinStrafeSeconds = movementJiggleMaxReversalIntervalSeconds
+	// Max delay between the end of the strafe and a shot to consider the strafe as a wide swing.
+	movementWideSwingShotWindowSeconds = 0.5
+	// Max delay between 2 duck presses to be part of the same crouch spam.
+	movementCrouchSpamMaxIntervalSeconds = 0.5
+	movementCrouchSpamMinDuckCount       = 3
+	// A crouch spam is reported only if the player shot or was involved in a damage within this delay, i.e. during a fight.
+	movementCrouchSpamFightWindowSeconds = 1.0
+)
+
+type MovementEvent struct {
+	Frame           int                         `json:"frame"`
+	Tick            int                         `json:"tick"`
+	EndFrame        int                         `json:"endFrame"`
+	EndTick         int                         `json:"endTick"`
+	RoundNumber     int                         `json:"roundNumber"`
+	Type            constants.MovementEventType `json:"type"`
+	PlayerName      string                      `json:"playerName"`
+	PlayerSteamID64 uint64                      `json:"playerSteamId"`
+	PlayerSide      common.Team                 `json:"playerSide"`
+	PlayerTeamName  string                      `json:"playerTeamName"`
+	// Number of jumps for bhop chains, direction reversals for jiggle peeks, duck presses for crouch spams and shots
+	// for wide swings.
+	Count      int                  `json:"count"`
+	WeaponName constants.WeaponName `json:"weaponName"` // Jump-shots and wide swings only
+}
+
+type movementSample struct {
+	start *funData.PlayerButtons
+	end   *funData.PlayerButtons
+	count int
+}
+
+type bhopChainTracker struct {
+	minIntervalTicks int
+	maxIntervalTicks int
+	jumpPressed      bool
+	chainStart       *funData.PlayerButtons
+	lastJump         *funData.PlayerButtons
+	jumpCount        int
+	samples          []movementSample
+}
+
+func newBhopChainTracker(tickRate float64) bhopChainTracker {
+	return bhopChainTracker{
+		minIntervalTicks: max(1, int(tickRate*movementJumpMinIntervalSeconds)),
+		maxIntervalTicks: max(1, int(tickRate*movementJumpMaxIntervalSeconds)),
+	}
+}
+
+func (tracker *bhopChainTracker) completeSample() {
+	if tracker.jumpCount >= movementBhopMinJumpCount {
+		tracker.samples = append(tracker.samples, movementSample{
+			start: tracker.chainStart,
+			end:   tracker.lastJump,
+			count: tracker.jumpCount,
+		})
+	}
+
+	tracker.chainStart = nil
+	tracker.lastJump = nil
+	tracker.jumpCount = 0
+}
+
+func (tracker *bhopChainTracker) apply(state *funData.PlayerButtons) {
+	jumpPressed := isButtonPressed(state.Buttons, common.ButtonJump)
+	isNewJump := jumpPressed && !tracker.jumpPressed
+	tracker.jumpPressed = jumpPressed
+	if !isNewJump {
+		return
+	}
+
+	if tracker.lastJump != nil {
+		interval := state.Tick - tracker.lastJump.Tick
+		if interval < tracker.minIntervalTicks {
+			return
+		}
+		if interval <= tracker.maxIntervalTicks {
+			tracker.lastJump = state
+			tracker.jumpCount++
+			return
+		}
+		tracker.completeSample()
+	}
+
+	tracker.chainStart = state
+	tracker.lastJump = state
+	tracker.jumpCount = 1
+}
+
+type jigglePeekTracker struct {
+	maxIntervalTicks int
+	direction        int
+	directionStart   *funData.PlayerButtons
+	chainStart       *funData.PlayerButtons
+	lastReversal     *funData.PlayerButtons
+	reversalCount    int
+	hasAttackInChain bool
+	samples          []movementSample
+}
+
+func newJigglePeekTracker(tickRate float64) jigglePeekTracker {
+	return jigglePeekTracker{
+		maxIntervalTicks: max(1, int(tickRate*movementJiggleMaxReversalIntervalSeconds)),
+	}
+}
+
+func strafeDirection(mask uint64) int {
+	leftPressed := isButtonPressed(mask, common.ButtonMoveLeft)
+	rightPressed := isButtonPressed(mask, common.ButtonMoveRight)
+	if leftPressed == rightPressed {
+		return 0
+	}
+	if leftPressed {
+		return -1
+	}
+
+	return 1
+}
+
+func (tracker *jigglePeekTracker) completeSample() {
+	if tracker.reversalCount >= movementJiggleMinReversalCount && !tracker.hasAttackInChain {
+		tracker.samples = append(tracker.samples, movementSample{
+			start: tracker.chainStart,
+			end:   tracker.lastReversal,
+			count: tracker.reversalCount,
+		})
+	}
+
+	tracker.chainStart = nil
+	tracker.lastReversal = nil
+	tracker.reversalCount = 0
+	tracker.hasAttackInChain = false
+}
+
+func (tracker *jigglePeekTracker) apply(state *funData.PlayerButtons) {
+	if isButtonPressed(state.Buttons, common.ButtonAttack) {
+		tracker.hasAttackInChain = true
+	}
+
+	direction := strafeDirection(state.Buttons)
+	if direction == 0 || direction == tracker.direction {
+		return
+	}
+
+	if tracker.directionStart != nil && state.Tick-tracker.directionStart.Tick <= tracker.maxIntervalTicks {
+		if tracker.chainStart == nil {
+			tracker.chainStart = tracker.directionStart
+		}
+		tracker.lastReversal = state
+		tracker.reversalCount++
+	} else {
+		tracker.completeSample()
+		tracker.hasAttackInChain = isButtonPressed(state.Buttons, common.ButtonAttack)
+	}
+
+	tracker.direction = direction
+	tracker.directionStart = state
+}
+
+type wideSwingTracker struct {
+	minStrafeTicks int
+	direction      int
+	directionStart *funData.PlayerButtons
+	lastState      *funData.PlayerButtons
+	samples        []movementSample
+}
+
+func newWideSwingTracker(tickRate float64) wideSwingTracker {
+	return wideSwingTracker{
+		minStrafeTicks: max(1, int(tickRate*movementWideSwingMinStrafeSeconds)),
+	}
+}
+
+// The sample ends with the state that released or reversed the strafe, or with the last state of the round.
+func (tracker *wideSwingTracker) completeSample(end *funData.PlayerButtons) {
+	if tracker.direction != 0 && end != nil && end.Tick-tracker.directionStart.Tick >= tracker.minStrafeTicks {
+		tracker.samples = append(tracker.samples, movementSample{
+			start: tracker.directionStart,
+			end:   end,
+		})
+	}
+
+	tracker.direction = 0
+	tracker.directionStart = nil
+}
+
+func (tracker *wideSwingTracker) apply(state *funData.PlayerButtons) {
+	tracker.lastState = state
+	direction := strafeDirection(state.Buttons)
+	if direction == tracker.direction {
+		return
+	}
+
+	tracker.completeSample(state)
+	if direction != 0 {
+		tracker.direction = direction
+		tracker.directionStart = state
+	}
+}
+
+type crouchSpamTracker struct {
+	maxIntervalTicks int
+	duckPressed      bool
+	chainStart       *funData.PlayerButtons
+	lastDuck         *funData.PlayerButtons
+	duckCount        int
+	samples          []movementSample
+}
+
+func newCrouchSpamTracker(tickRate float64) crouchSpamTracker {
+	return crouchSpamTracker{
+		maxIntervalTicks: max(1, int(tickRate*movementCrouchSpamMaxIntervalSeconds)),
+	}
+}
+
+func (tracker *crouchSpamTracker) completeSample() {
+	if tracker.duckCount >= movementCrouchSpamMinDuckCount {
+		tracker.samples = append(tracker.samples, movementSample{
+			start: tracker.chainStart,
+			end:   tracker.lastDuck,
+			count: tracker.duckCount,
+		})
+	}
+
+	tracker.chainStart = nil
+	tracker.lastDuck = nil
+	tracker.duckCount = 0
+}
+
+func (tracker *crouchSpamTracker) apply(state *funData.PlayerButtons) {
+	duckPressed := isButtonPressed(state.Buttons, common.ButtonDuck)
+	isNewDuck := duckPressed && !tracker.duckPressed
+	tracker.duckPressed = duckPressed
+	if !isNewDuck {
+		return
+	}
+
+	if tracker.lastDuck != nil && state.Tick-tracker.lastDuck.Tick <= tracker.maxIntervalTicks {
+		tracker.lastDuck = state
+		tracker.duckCount++
+		return
+	}
+
+	tracker.completeSample()
+	tracker.chainStart = state
+	tracker.lastDuck = state
+	tracker.duckCount = 1
+}
+
+func jumpPressesFromButtons(buttons []*funData.PlayerButtons) []*funData.PlayerButtons {
+	var jumps []*funData.PlayerButtons
+	jumpPressed := false
+	for _, state := range buttons {
+		pressed := isButtonPressed(state.Buttons, common.ButtonJump)
+		if pressed && !jumpPressed {
+			jumps = append(jumps, state)
+		}
+		jumpPressed = pressed
+	}
+
+	return jumps
+}
+
+func hasFightActivityWithinWindow(startTick int, endTick int, shots []*Shot, damageTicks []int, windowTicks int) bool {
+	for _, shot := range shots {
+		if shot.Tick >= startTick-windowTicks && shot.Tick <= endTick+windowTicks {
+			return true
+		}
+	}
+	for _, tick := range damageTicks {
+		if tick >= startTick-windowTicks && tick <= endTick+windowTicks {
+			return true
+		}
+	}
+
+	return false
+}
+
+func generateMovementEvents(match *Match) {
+	if match == nil {
+		return
+	}
+
+	tickRate := match.TickRate
+	if tickRate <= 0 {
+		tickRate = defaultTickRateForDerivedTables
+	}
+	jumpShotWindowTicks := max(1, int(tickRate*movementJumpShotWindowSeconds))
+	fightWindowTicks := max(1, int(tickRate*movementCrouchSpamFightWindowSeconds))
+	wideSwingShotWindowTicks := max(1, int(tickRate*movementWideSwingShotWindowSeconds))
+
+	buttonsByRoundPlayer := buildPlayerButtonsByRoundPlayer(match)
+	shotsByRoundPlayer := make(map[roundPlayerKey][]*Shot)
+	for _, shot := range match.Shots {
+		if shot == nil || shot.IsPlayerControllingBot {
+			continue
+		}
+		key := roundPlayerKey{roundNumber: shot.RoundNumber, steamID64: shot.PlayerSteamID64}
+		shotsByRoundPlayer[key] = append(shotsByRoundPlayer[key], shot)
+	}
+	damageTicksByRoundPlayer := make(map[roundPlayerKey][]int)
+	for _, damage := range match.Damages {
+		if damage == nil {
+			continue
+		}
+		for _, steamID64 := range []uint64{damage.AttackerSteamID64, damage.VictimSteamID64} {
+			if steamID64 == 0 {
+				continue
+			}
+			key := roundPlayerKey{roundNumber: damage.RoundNumber, steamID64: steamID64}
+			damageTicksByRoundPlayer[key] = append(damageTicksByRoundPlayer[key], damage.Tick)
+		}
+	}
+
+	derived := []*MovementEvent{}
+	for key, buttons := range buttonsByRoundPlayer {
+		if len(buttons) == 0 {
+			continue
+		}
+
+		playerName := buttons[0].Name
+		side, teamName := match.playerSideAtRound(key.steamID64, key.roundNumber)
+		newEvent := func(eventType constants.MovementEventType, sample movementSample) *MovementEvent {
+			return &MovementEvent{
+				Frame:           sample.start.Frame,
+				Tick:            sample.start.Tick,
+				EndFrame:        sample.end.Frame,
+				EndTick:         sample.end.Tick,
+				RoundNumber:     key.roundNumber,
+				Type:            eventType,
+				PlayerName:      playerName,
+				PlayerSteamID64: key.steamID64,
+				PlayerSide:      side,
+				PlayerTeamName:  teamName,
+				Count:           sample.count,
+			}
+		}
+
+		bhopTracker := newBhopChainTracker(tickRate)
+		jiggleTracker := newJigglePeekTracker(tickRate)
+		crouchTracker := newCrouchSpamTracker(tickRate)
+		swingTracker := newWideSwingTracker(tickRate)
+		for _, state := range buttons {
+			bhopTracker.apply(state)
+			jiggleTracker.apply(state)
+			crouchTracker.apply(state)
+			swingTracker.apply(state)
+		}
+		bhopTracker.completeSample()
+		jiggleTracker.completeSample()
+		crouchTracker.completeSample()
+		swingTracker.completeSample(swingTracker.lastState)
+
+		for _, sample := range bhopTracker.samples {
+			derived = append(derived, newEvent(constants.MovementEventTypeBhopChain, sample))
+		}
+		for _, sample := range jiggleTracker.samples {
+			derived = append(derived, newEvent(constants.MovementEventTypeJigglePeek, sample))
+		}
+		for _, sample := range crouchTracker.samples {
+			if hasFightActivityWithinWindow(sample.start.Tick, sample.end.Tick, shotsByRoundPlayer[key], damageTicksByRoundPlayer[key], fightWindowTicks) {
+				derived = append(derived, newEvent(constants.MovementEventTypeCrouchSpam, sample))
+			}
+		}
+
+		// Unlike jiggle peeks, wide swings are made to take a fight, the strafe must be followed by a shot.
+		for _, sample := range swingTracker.samples {
+			var shots []*Shot
+			for _, shot := range shotsByRoundPlayer[key] {
+				if shot.WeaponType != constants.WeaponTypeGrenade && shot.Tick >= sample.start.Tick && shot.Tick <= sample.end.Tick+wideSwingShotWindowTicks {
+					shots = append(shots, shot)
+				}
+			}
+			if len(shots) == 0 {
+				continue
+			}
+
+			sample.count = len(shots)
+			event := newEvent(constants.MovementEventTypeWideSwing, sample)
+			event.WeaponName = shots[0].WeaponName
+			derived = append(derived, event)
+		}
+
+		jumps := jumpPressesFromButtons(buttons)
+		for _, shot := range shotsByRoundPlayer[key] {
+			if !shot.IsPlayerAirborne || shot.WeaponType == constants.WeaponTypeGrenade {
+				continue
+			}
+
+			var lastJump *funData.PlayerButtons
+			for _, jump := range jumps {
+				if jump.Tick > shot.Tick {
+					break
+				}
+				lastJump = jump
+			}
+			if lastJump == nil || shot.Tick-lastJump.Tick > jumpShotWindowTicks {
+				continue
+			}
+
+			event := newEvent(constants.MovementEventTypeJumpShot, movementSample{start: lastJump, end: lastJump, count: 1})
+			event.EndFrame = shot.Frame
+			event.EndTick = shot.Tick
+			event.WeaponName = shot.WeaponName
+			derived = append(derived, event)
+		}
+	}
+
+	sort.Slice(derived, func(i int, j int) bool {
+		if derived[i].Tick != derived[j].Tick {
+			return derived[i].Tick < derived[j].Tick
+		}
+		if derived[i].PlayerSteamID64 != derived[j].PlayerSteamID64 {
+			return derived[i].PlayerSteamID64 < derived[j].PlayerSteamID64
+		}
+		if derived[i].Type != derived[j].Type {
+			return derived[i].Type < derived[j].Type
+		}
+
+		return derived[i].EndTick < derived[j].EndTick
+	})
+
+	match.MovementEvents = derived
+}
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/akiver/cs-demo-analyzer/pkg/api/constants"
+	"github.com/akiver/cs-demo-analyzer/pkg/api/funData"
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
+)
+
+func buttonStates(ticks []int, masks []common.ButtonBitMask) []*funData.PlayerButtons {
+	states := make([]*funData.PlayerButtons, 0, len(ticks))
+	for index, tick := range ticks {
+		states = append(states, &funData.PlayerButtons{Frame: tick, Tick: tick, RoundNumber: 1, SteamID64: 1, Buttons: uint64(masks[index])})
+	}
+
+	return states
+}
+
+func TestBhopChainTracker_DetectsChainAndIgnoresAirborneSpam(t *testing.T) {
+	tracker := newBhopChainTracker(64)
+	states := buttonStates(
+		[]int{0, 2, 10, 12, 50, 52, 100, 102, 300, 302},
+		[]common.ButtonBitMask{common.ButtonJump, 0, common.ButtonJump, 0, common.ButtonJump, 0, common.ButtonJump, 0, common.ButtonJump, 0},
+	)
+	for _, state := range states {
+		tracker.apply(state)
+	}
+	tracker.completeSample()
+
+	if len(tracker.samples) != 1 {
+		t.Fatalf("expected 1 bhop chain, got %d", len(tracker.samples))
+	}
+	sample := tracker.samples[0]
+	if sample.count != 3 || sample.start.Tick != 0 || sample.end.Tick != 100 {
+		t.Fatalf("expected chain of 3 jumps from 0 to 100, got %d jumps from %d to %d", sample.count, sample.start.Tick, sample.end.Tick)
+	}
+}
+
+func TestJigglePeekTracker_RequiresFastReversalsWithoutShooting(t *testing.T) {
+	ticks := []int{0, 10, 20, 30, 40}
+	directions := []common.ButtonBitMask{common.ButtonMoveLeft, common.ButtonMoveRight, common.ButtonMoveLeft, common.ButtonMoveRight, 0}
+
+	tracker := newJigglePeekTracker(64)
+	for _, state := range buttonStates(ticks, directions) {
+		tracker.apply(state)
+	}
+	tracker.completeSample()
+	if len(tracker.samples) != 1 || tracker.samples[0].count != 3 {
+		t.Fatalf("expected 1 jiggle peek with 3 reversals, got %v", tracker.samples)
+	}
+
+	directions[2] |= common.ButtonAttack
+	tracker = newJigglePeekTracker(64)
+	for _, state := range buttonStates(ticks, directions) {
+		tracker.apply(state)
+	}
+	tracker.completeSample()
+	if len(tracker.samples) != 0 {
+		t.Fatalf("expected jiggle peek with a shot to be ignored")
+	}
+}
+
+func TestGenerateMovementEvents_CrouchSpamRequiresFight(t *testing.T) {
+	buttons := buttonStates(
+		[]int{0, 5, 20, 25, 40, 45},
+		[]common.ButtonBitMask{common.ButtonDuck, 0, common.ButtonDuck, 0, common.ButtonDuck, 0},
+	)
+	match := &Match{TickRate: 64, PlayerButtons: buttons}
+
+	generateMovementEvents(match)
+	if len(match.MovementEvents) != 0 {
+		t.Fatalf("expected crouch spam outside of a fight to be ignored, got %d events", len(match.MovementEvents))
+	}
+
+	match.Shots = []*Shot{{Tick: 60, RoundNumber: 1, PlayerSteamID64: 1, WeaponName: constants.WeaponAK47, WeaponType: constants.WeaponTypeRifle}}
+	generateMovementEvents(match)
+	if len(match.MovementEvents) != 1 || match.MovementEvents[0].Type != constants.MovementEventTypeCrouchSpam {
+		t.Fatalf("expected 1 crouch spam event, got %d events", len(match.MovementEvents))
+	}
+}
+
+func TestGenerateMovementEvents_DetectsJumpShot(t *testing.T) {
+	buttons := buttonStates([]int{100, 102}, []common.ButtonBitMask{common.ButtonJump, 0})
+	match := &Match{
+		TickRate:      64,
+		PlayerButtons: buttons,
+		Shots: []*Shot{
+			{Frame: 120, Tick: 120, RoundNumber: 1, PlayerSteamID64: 1, WeaponName: constants.WeaponScout, WeaponType: constants.WeaponTypeSniper, IsPlayerAirborne: true},
+			{Frame: 300, Tick: 300, RoundNumber: 1, PlayerSteamID64: 1, WeaponName: constants.WeaponScout, WeaponType: constants.WeaponTypeSniper, IsPlayerAirborne: true},
+		},
+	}
+
+	generateMovementEvents(match)
+
+	if len(match.MovementEvents) != 1 {
+		t.Fatalf("expected 1 jump shot, got %d events", len(match.MovementEvents))
+	}
+	event := match.MovementEvents[0]
+	if event.Type != constants.MovementEventTypeJumpShot || event.Tick != 100 || event.EndTick != 120 || event.WeaponName != constants.WeaponScout {
+		t.Fatalf("unexpected jump shot event %+v", event)
+	}
+}
+
+func TestGenerateMovementEvents_WideSwingRequiresLongStrafeAndShot(t *testing.T) {
+	// A right strafe held 32 ticks (0.5s) and a short left one of 10 ticks.
+	buttons := buttonStates(
+		[]int{0, 32, 100, 110},
+		[]common.ButtonBitMask{common.ButtonMoveRight, 0, common.ButtonMoveLeft, 0},
+	)
+	match := &Match{TickRate: 64, PlayerButtons: buttons}
+
+	generateMovementEvents(match)
+	if len(match.MovementEvents) != 0 {
+		t.Fatalf("expected a strafe without shot to be ignored, got %d events", len(match.MovementEvents))
+	}
+
+	match.Shots = []*Shot{
+		{Tick: 40, RoundNumber: 1, PlayerSteamID64: 1, WeaponName: constants.WeaponAK47, WeaponType: constants.WeaponTypeRifle},
+		{Tick: 45, RoundNumber: 1, PlayerSteamID64: 1, WeaponName: constants.WeaponAK47, WeaponType: constants.WeaponTypeRifle},
+		{Tick: 112, RoundNumber: 1, PlayerSteamID64: 1, WeaponName: constants.WeaponAK47, WeaponType: constants.WeaponTypeRifle},
+	}
+	generateMovementEvents(match)
+	if len(match.MovementEvents) != 1 {
+		t.Fatalf("expected 1 wide swing, got %d events", len(match.MovementEvents))
+	}
+	event := match.MovementEvents[0]
+	if event.Type != constants.MovementEventTypeWideSwing || event.Tick != 0 || event.EndTick != 32 || event.Count != 2 || event.WeaponName != constants.WeaponAK47 {
+		t.Fatalf("unexpected wide swing event %+v", event)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"math"
 	"sort"
 
+	"github.com/akiver/cs-demo-analyzer/internal/demo"
 	"github.com/akiver/cs-demo-analyzer/internal/strings"
 	"github.com/akiver/cs-demo-analyzer/pkg/api/constants"
 	"github.com/akiver/cs-demo-analyzer/pkg/api/funData"
@@ -112,6 +113,19 @@ type PlayerJSON struct {
 	WallbangKillCount           int     `json:"wallbangKillCount"`
 	AwpHoldKillCount            int     `json:"awpHoldKillCount"`
 	AwpHoldDeathCount           int     `json:"awpHoldDeathCount"`
+	BhopChainCount              int     `json:"bhopChainCount"`
+	JigglePeekCount             int     `json:"jigglePeekCount"`
+	WideSwingCount              int     `json:"wideSwingCount"`
+	CrouchSpamCount             int     `json:"crouchSpamCount"`
+	JumpShotCount               int     `json:"jumpShotCount"`
+	BombCarryTime               float64 `json:"bombCarryTime"`
+	RoundsPlayedCount           int     `json:"roundsPlayedCount"`
+	KillPerRoundPlayed          float32 `json:"killPerRoundPlayed"`
+	AssistPerRoundPlayed        float32 `json:"assistPerRoundPlayed"`
+	DeathPerRoundPlayed         float32 `json:"deathPerRoundPlayed"`
+	DamagePerRoundPlayed        float32 `json:"damagePerRoundPlayed"`
+	UtilityDamagePerRoundPlayed float32 `json:"utilityDamagePerRoundPlayed"`
+	CrosshairSettings           *CrosshairSettings `json:"crosshairSettings"`
 	TeamAttackDamage            int     `json:"teamAttackDamage"`
 	TeamUtilityDamage           int     `json:"teamUtilityDamage"`
 	TeamFlashDuration           float32 `json:"teamFlashDuration"`
@@ -787,6 +801,19 @@ func (player *Player) MarshalJSON() ([]byte, error) {
 		CounterStrafingComboPerfectRate: player.CounterStrafingComboPerfectRate(),
 		AwpHoldKillCount:            player.AwpHoldKillCount(),
 		AwpHoldDeathCount:           player.AwpHoldDeathCount(),
+		BhopChainCount:              player.BhopChainCount(),
+		JigglePeekCount:             player.JigglePeekCount(),
+		WideSwingCount:              player.WideSwingCount(),
+		CrouchSpamCount:             player.CrouchSpamCount(),
+		JumpShotCount:               player.JumpShotCount(),
+		BombCarryTime:               player.BombCarryTime(),
+		RoundsPlayedCount:           player.RoundsPlayedCount(),
+		KillPerRoundPlayed:          player.AverageKillPerRoundPlayed(),
+		AssistPerRoundPlayed:        player.AverageAssistPerRoundPlayed(),
+		DeathPerRoundPlayed:         player.AverageDeathPerRoundPlayed(),
+		DamagePerRoundPlayed:        player.AverageDamagePerRoundPlayed(),
+		UtilityDamagePerRoundPlayed: player.UtilityDamagePerRoundPlayed(),
+		CrosshairSettings:           player.CrosshairSettings(),
 	})
 }
 
@@ -921,6 +948,47 @@ func (player *Player) AwpHoldDeathCount() int {
 	return count
 }
 
+func (player *Player) movementEventCount(eventType constants.MovementEventType) int {
+	var count int
+	for _, event := range player.match.MovementEvents {
+		if event.PlayerSteamID64 == player.SteamID64 && event.Type == eventType {
+			count++
+		}
+	}
+	return count
+}
+
+func (player *Player) BhopChainCount() int {
+	return player.movementEventCount(constants.MovementEventTypeBhopChain)
+}
+
+func (player *Player) JigglePeekCount() int {
+	return player.movementEventCount(constants.MovementEventTypeJigglePeek)
+}
+
+func (player *Player) WideSwingCount() int {
+	return player.movementEventCount(constants.MovementEventTypeWideSwing)
+}
+
+func (player *Player) CrouchSpamCount() int {
+	return player.movementEventCount(constants.MovementEventTypeCrouchSpam)
+}
+
+func (player *Player) JumpShotCount() int {
+	return player.movementEventCount(constants.MovementEventTypeJumpShot)
+}
+
+// This returns the number of seconds the player carried the bomb while alive after the freeze time.
+func (player *Player) BombCarryTime() float64 {
+	var seconds float64
+	for _, movement := range player.match.PlayerRoundMovements {
+		if movement.PlayerSteamID64 == player.SteamID64 {
+			seconds += movement.BombCarryTime
+		}
+	}
+	return seconds
+}
+
 func (player *Player) TeamName() string {
 	return player.Team.Name
 }
@@ -1450,7 +1518,7 @@ func (player *Player) FirstShotHitCount() int {
 			continue
 		}
 
-		matchedShot := nearestPriorShotForDamage(damage, shots)
+		matchedShot := nearestPriorShotForDamage(damage, shots, player.match.config().DamageAttributionMaxShotFrameDistance)
 		if matchedShot == nil || !isFirstShotOfFiringSequence(matchedShot) {
 			continue
 		}
@@ -1519,7 +1587,7 @@ func (player *Player) shotsByWeaponID() map[string][]shotIndexEntry {
 	return shotsByWeaponID
 }
 
-func nearestPriorShotForDamage(damage *Damage, shotsByWeaponID map[string][]shotIndexEntry) *Shot {
+func nearestPriorShotForDamage(damage *Damage, shotsByWeaponID map[string][]shotIndexEntry, maxFrameDistance int) *Shot {
 	entries, exists := shotsByWeaponID[damage.WeaponUniqueID]
 	if !exists || len(entries) == 0 {
 		return nil
@@ -1541,7 +1609,7 @@ func nearestPriorShotForDamage(damage *Damage, shotsByWeaponID map[string][]shot
 		}
 
 		frameDelta := absInt(entry.frame - damage.Frame)
-		if frameDelta > constants.HeuristicDamageAttributionMaxShotFrameDistance {
+		if frameDelta > maxFrameDistance {
 			continue
 		}
 
@@ -1671,10 +1739,76 @@ func (player *Player) HltvRating() float32 {
 	return rating
 }
 
+// This returns the number of rounds during which the player was connected and on a team while the round was live.
+func (player *Player) RoundsPlayedCount() int {
+	var count int
+	for _, round := range player.match.Rounds {
+		if player.match.playerRoundsPlayed[roundPlayerKey{roundNumber: round.Number, steamID64: player.SteamID64}] {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Returns nil when the player has no crosshair share code or if it can't be decoded.
+func (player *Player) CrosshairSettings() *CrosshairSettings {
+	if player.CrosshairShareCode == "" {
+		return nil
+	}
+
+	decoded, err := demo.DecodeCrosshairShareCode(player.CrosshairShareCode)
+	if err != nil {
+		return nil
+	}
+	settings := CrosshairSettings(decoded)
+
+	return &settings
+}
+
 func (player *Player) roundCount() int {
 	return len(player.match.Rounds)
 }
 
+// "Per round played" stats are normalized by the rounds actually played, it fallbacks to the match rounds count when
+// the presence of the player has not been detected (POV demos for example).
+func (player *Player) playedRoundCount() int {
+	if roundsPlayedCount := player.RoundsPlayedCount(); roundsPlayedCount > 0 {
+		return roundsPlayedCount
+	}
+
+	return player.roundCount()
+}
+
+func (player *Player) ratePerRoundPlayed(value int) float32 {
+	roundCount := player.playedRoundCount()
+	if value <= 0 || roundCount <= 0 {
+		return 0
+	}
+
+	return float32(value) / float32(roundCount)
+}
+
+func (player *Player) AverageKillPerRoundPlayed() float32 {
+	return player.ratePerRoundPlayed(player.KillCount())
+}
+
+func (player *Player) AverageAssistPerRoundPlayed() float32 {
+	return player.ratePerRoundPlayed(player.AssistCount())
+}
+
+func (player *Player) AverageDeathPerRoundPlayed() float32 {
+	return player.ratePerRoundPlayed(player.DeathCount())
+}
+
+func (player *Player) AverageDamagePerRoundPlayed() float32 {
+	return player.ratePerRoundPlayed(player.HealthDamage())
+}
+
+func (player *Player) UtilityDamagePerRoundPlayed() float32 {
+	return player.ratePerRoundPlayed(player.UtilityDamage())
+}
+
 func (player *Player) oneVsXClutches(opponentCount int) []*Clutch {
 	clutches := player.Clutches()
 	for _, clutch := range player.match.Clutches {
@@ -0,0 +1,262 @@
+package api
+
+import (
+	"sort"
+
+	"github.com/akiver/cs-demo-analyzer/pkg/api/constants"
+	"github.com/golang/geo/r3"
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
+)
+
+// Reference values used to scale role evidences between 0 and 1, a player reaching the reference gets the max score.
+const (
+	roleEntryOpeningDuelRateReference       = 0.35   // Involved in the opening duel of 35% of the rounds
+	roleAwperAwpRoundShareReference         = 0.5    // Fired the AWP in half of the rounds where the player fired
+	roleLurkerTeammateDistanceReference     = 1500.0 // Average distance to the nearest teammate in game units
+	roleAnchorBombsiteOccupancyReference    = 0.8    // In a bombsite zone in 80% of the setup snapshots
+	roleSupportUtilityPerRoundReference     = 2.5
+	roleSupportFlashAssistPerRoundReference = 0.25
+)
+
+type PlayerRoleEvidence struct {
+	SteamID64                      uint64               `json:"steamId"`
+	Name                           string               `json:"name"`
+	TeamName                       string               `json:"teamName"`
+	Side                           common.Team          `json:"side"`
+	RoundCount                     int                  `json:"roundCount"`
+	PrimaryRole                    constants.PlayerRole `json:"primaryRole"`
+	SecondaryRole                  constants.PlayerRole `json:"secondaryRole"`
+	EntryScore                     float64              `json:"entryScore"`
+	LurkerScore                    float64              `json:"lurkerScore"`
+	AwperScore                     float64              `json:"awperScore"`
+	AnchorScore                    float64              `json:"anchorScore"`
+	SupportScore                   float64              `json:"supportScore"`
+	OpeningDuelCount               int                  `json:"openingDuelCount"`
+	AwpRoundShare                  float64              `json:"awpRoundShare"`
+	AverageNearestTeammateDistance float64              `json:"averageNearestTeammateDistance"`
+	BombsiteOccupancy              float64              `json:"bombsiteOccupancy"`
+	UtilityThrownCount             int                  `json:"utilityThrownCount"`
+	FlashAssistCount               int                  `json:"flashAssistCount"`
+}
+
+type playerSideKey struct {
+	steamID64 uint64
+	side      common.Team
+}
+
+type playerRoleAccumulator struct {
+	evidence            *PlayerRoleEvidence
+	roundsWithShots     map[int]bool
+	roundsWithAwpShots  map[int]bool
+	teammateDistanceSum float64
+	teammateSampleCount int
+	bombsiteSampleCount int
+	zoneSampleCount     int
+}
+
+func capScore(value float64, reference float64) float64 {
+	if reference <= 0 {
+		return 0
+	}
+
+	return min(1, value/reference)
+}
+
+func (evidence *PlayerRoleEvidence) assignRoles() {
+	scores := []struct {
+		role  constants.PlayerRole
+		score float64
+	}{
+		{constants.PlayerRoleAwper, evidence.AwperScore},
+		{constants.PlayerRoleEntry, evidence.EntryScore},
+		{constants.PlayerRoleAnchor, evidence.AnchorScore},
+		{constants.PlayerRoleLurker, evidence.LurkerScore},
+		{constants.PlayerRoleSupport, evidence.SupportScore},
+	}
+	// Stable sort so that ties are resolved with the order above.
+	sort.SliceStable(scores, func(i int, j int) bool {
+		return scores[i].score > scores[j].score
+	})
+
+	if scores[0].score > 0 {
+		evidence.PrimaryRole = scores[0].role
+	}
+	if scores[1].score > 0 {
+		evidence.SecondaryRole = scores[1].role
+	}
+}
+
+// This returns the role evidences of each player for each side played.
+// Roles are inferred from opening duels, AWP usage, distance from teammates and bombsite occupancy during team setups,
+// utility thrown and flash assists.
+func (match *Match) PlayerRoles() []*PlayerRoleEvidence {
+	accumulators := make(map[playerSideKey]*playerRoleAccumulator)
+	getAccumulator := func(steamID64 uint64, side common.Team) *playerRoleAccumulator {
+		if side != common.TeamTerrorists && side != common.TeamCounterTerrorists {
+			return nil
+		}
+
+		key := playerSideKey{steamID64: steamID64, side: side}
+		accumulator, exists := accumulators[key]
+		if !exists {
+			player := match.PlayersBySteamID[steamID64]
+			if player == nil || player.Team == nil {
+				return nil
+			}
+			accumulator = &playerRoleAccumulator{
+				evidence: &PlayerRoleEvidence{
+					SteamID64: steamID64,
+					Name:      player.Name,
+					TeamName:  player.Team.Name,
+					Side:      side,
+				},
+				roundsWithShots:    make(map[int]bool),
+				roundsWithAwpShots: make(map[int]bool),
+			}
+			accumulators[key] = accumulator
+		}
+
+		return accumulator
+	}
+
+	for _, player := range match.PlayersBySteamID {
+		if player.Team == nil {
+			continue
+		}
+		for _, round := range match.Rounds {
+			side := round.TeamASide
+			if player.Team.Name == round.TeamBName {
+				side = round.TeamBSide
+			} else if player.Team.Name != round.TeamAName {
+				continue
+			}
+			if accumulator := getAccumulator(player.SteamID64, side); accumulator != nil {
+				accumulator.evidence.RoundCount++
+			}
+		}
+	}
+
+	// Kills are sorted chronologically, the 1st kill between opponents of a round is its opening duel.
+	for _, kills := range match.KillsByRound() {
+		for _, kill := range kills {
+			if kill.KillerSteamID64 == 0 || kill.VictimSteamID64 == 0 || kill.KillerSide == kill.VictimSide {
+				continue
+			}
+			if accumulator := getAccumulator(kill.KillerSteamID64, kill.KillerSide); accumulator != nil {
+				accumulator.evidence.OpeningDuelCount++
+			}
+			if accumulator := getAccumulator(kill.VictimSteamID64, kill.VictimSide); accumulator != nil {
+				accumulator.evidence.OpeningDuelCount++
+			}
+			break
+		}
+	}
+
+	for _, shot := range match.Shots {
+		if shot.IsPlayerControllingBot || shot.WeaponType == constants.WeaponTypeGrenade || shot.WeaponType == constants.WeaponTypeEquipment {
+			continue
+		}
+		accumulator := getAccumulator(shot.PlayerSteamID64, shot.PlayerSide)
+		if accumulator == nil {
+			continue
+		}
+		accumulator.roundsWithShots[shot.RoundNumber] = true
+		if shot.WeaponName == constants.WeaponAWP {
+			accumulator.roundsWithAwpShots[shot.RoundNumber] = true
+		}
+	}
+
+	for _, setup := range match.TeamSetups {
+		for _, playerZone := range setup.PlayerZones {
+			accumulator := getAccumulator(playerZone.SteamID64, setup.Side)
+			if accumulator == nil {
+				continue
+			}
+
+			if playerZone.Zone != "" {
+				accumulator.zoneSampleCount++
+				if bombsiteFromPlaceName(playerZone.Zone) != "" {
+					accumulator.bombsiteSampleCount++
+				}
+			}
+
+			nearestDistance := -1.0
+			for _, teammateZone := range setup.PlayerZones {
+				if teammateZone.SteamID64 == playerZone.SteamID64 {
+					continue
+				}
+				playerPosition := r3.Vector{X: playerZone.X, Y: playerZone.Y, Z: playerZone.Z}
+				teammatePosition := r3.Vector{X: teammateZone.X, Y: teammateZone.Y, Z: teammateZone.Z}
+				distance := playerPosition.Sub(teammatePosition).Norm()
+				if nearestDistance == -1 || distance < nearestDistance {
+					nearestDistance = distance
+				}
+			}
+			if nearestDistance >= 0 {
+				accumulator.teammateDistanceSum += nearestDistance
+				accumulator.teammateSampleCount++
+			}
+		}
+	}
+
+	for _, utility := range match.Utilities {
+		if accumulator := getAccumulator(utility.ThrowerSteamID64, utility.ThrowerSide); accumulator != nil {
+			accumulator.evidence.UtilityThrownCount++
+		}
+	}
+
+	for _, kill := range match.Kills {
+		if !kill.IsAssistedFlash || kill.AssisterSteamID64 == 0 || kill.AssisterSide == kill.VictimSide {
+			continue
+		}
+		if accumulator := getAccumulator(kill.AssisterSteamID64, kill.AssisterSide); accumulator != nil {
+			accumulator.evidence.FlashAssistCount++
+		}
+	}
+
+	evidences := make([]*PlayerRoleEvidence, 0, len(accumulators))
+	for _, accumulator := range accumulators {
+		evidence := accumulator.evidence
+		if evidence.RoundCount == 0 {
+			continue
+		}
+
+		roundCount := float64(evidence.RoundCount)
+		if len(accumulator.roundsWithShots) > 0 {
+			evidence.AwpRoundShare = float64(len(accumulator.roundsWithAwpShots)) / float64(len(accumulator.roundsWithShots))
+		}
+		if accumulator.teammateSampleCount > 0 {
+			evidence.AverageNearestTeammateDistance = accumulator.teammateDistanceSum / float64(accumulator.teammateSampleCount)
+		}
+		if accumulator.zoneSampleCount > 0 {
+			evidence.BombsiteOccupancy = float64(accumulator.bombsiteSampleCount) / float64(accumulator.zoneSampleCount)
+		}
+
+		evidence.EntryScore = capScore(float64(evidence.OpeningDuelCount)/roundCount, roleEntryOpeningDuelRateReference)
+		evidence.AwperScore = capScore(evidence.AwpRoundShare, roleAwperAwpRoundShareReference)
+		evidence.SupportScore = (capScore(float64(evidence.UtilityThrownCount)/roundCount, roleSupportUtilityPerRoundReference) +
+			capScore(float64(evidence.FlashAssistCount)/roundCount, roleSupportFlashAssistPerRoundReference)) / 2
+		// Lurking is a T side role and anchoring a CT side one.
+		if evidence.Side == common.TeamTerrorists {
+			evidence.LurkerScore = capScore(evidence.AverageNearestTeammateDistance, roleLurkerTeammateDistanceReference)
+		} else if evidence.Side == common.TeamCounterTerrorists {
+			evidence.AnchorScore = capScore(evidence.BombsiteOccupancy, roleAnchorBombsiteOccupancyReference)
+		}
+		evidence.assignRoles()
+
+		evidences = append(evidences, evidence)
+	}
+
+	sort.Slice(evidences, func(i int, j int) bool {
+		if evidences[i].TeamName != evidences[j].TeamName {
+			return evidences[i].TeamName < evidences[j].TeamName
+		}
+		if evidences[i].Name != evidences[j].Name {
+			return evidences[i].Name < evidences[j].Name
+		}
+
+		return evidences[i].Side < evidences[j].Side
+	})
+
+	return evidences
+}
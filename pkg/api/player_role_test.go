@@ -0,0 +1,114 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/akiver/cs-demo-analyzer/pkg/api/constants"
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
+)
+
+func TestPlayerRoles_InfersAwperAndEntry(t *testing.T) {
+	teamA := &Team{Name: "Team A"}
+	teamB := &Team{Name: "Team B"}
+	match := &Match{
+		PlayersBySteamID: map[uint64]*Player{
+			1: {SteamID64: 1, Name: "awper", Team: teamA},
+			2: {SteamID64: 2, Name: "entry", Team: teamB},
+		},
+		Rounds: []*Round{
+			{Number: 1, TeamAName: "Team A", TeamBName: "Team B", TeamASide: common.TeamCounterTerrorists, TeamBSide: common.TeamTerrorists},
+			{Number: 2, TeamAName: "Team A", TeamBName: "Team B", TeamASide: common.TeamCounterTerrorists, TeamBSide: common.TeamTerrorists},
+		},
+		Kills: []*Kill{
+			{RoundNumber: 1, KillerSteamID64: 2, KillerSide: common.TeamTerrorists, VictimSteamID64: 1, VictimSide: common.TeamCounterTerrorists},
+			{RoundNumber: 2, KillerSteamID64: 2, KillerSide: common.TeamTerrorists, VictimSteamID64: 3, VictimSide: common.TeamCounterTerrorists},
+			{RoundNumber: 2, KillerSteamID64: 1, KillerSide: common.TeamCounterTerrorists, VictimSteamID64: 2, VictimSide: common.TeamTerrorists},
+		},
+		Shots: []*Shot{
+			{RoundNumber: 1, PlayerSteamID64: 1, PlayerSide: common.TeamCounterTerrorists, WeaponName: constants.WeaponAWP, WeaponType: constants.WeaponTypeSniper},
+			{RoundNumber: 2, PlayerSteamID64: 1, PlayerSide: common.TeamCounterTerrorists, WeaponName: constants.WeaponAWP, WeaponType: constants.WeaponTypeSniper},
+		},
+	}
+
+	evidences := match.PlayerRoles()
+
+	if len(evidences) != 2 {
+		t.Fatalf("expected 2 role evidences, got %d", len(evidences))
+	}
+	awper, entry := evidences[0], evidences[1]
+	if awper.PrimaryRole != constants.PlayerRoleAwper || awper.AwperScore != 1 {
+		t.Fatalf("expected awper primary role, got %+v", awper)
+	}
+	if awper.SecondaryRole != constants.PlayerRoleEntry || awper.OpeningDuelCount != 1 {
+		t.Fatalf("expected entry secondary role from 1 opening duel, got %+v", awper)
+	}
+	if entry.PrimaryRole != constants.PlayerRoleEntry || entry.OpeningDuelCount != 2 || entry.SecondaryRole != "" {
+		t.Fatalf("expected entry primary role only, got %+v", entry)
+	}
+}
+
+func TestPlayerRoles_AssignRoles(t *testing.T) {
+	awpShots := []*Shot{
+		{RoundNumber: 1, PlayerSteamID64: 1, PlayerSide: common.TeamTerrorists, WeaponName: constants.WeaponAWP, WeaponType: constants.WeaponTypeSniper},
+		{RoundNumber: 2, PlayerSteamID64: 1, PlayerSide: common.TeamTerrorists, WeaponName: constants.WeaponAWP, WeaponType: constants.WeaponTypeSniper},
+	}
+	openingKills := []*Kill{
+		{RoundNumber: 1, KillerSteamID64: 1, KillerSide: common.TeamTerrorists, VictimSteamID64: 2, VictimSide: common.TeamCounterTerrorists},
+		{RoundNumber: 2, KillerSteamID64: 2, KillerSide: common.TeamCounterTerrorists, VictimSteamID64: 1, VictimSide: common.TeamTerrorists},
+	}
+	// The player is 2000 units away from their teammate during the setups.
+	lurkSetups := []*TeamSetup{
+		{RoundNumber: 1, TeamName: "Team A", Side: common.TeamTerrorists, PlayerZones: []SetupPlayerZone{{SteamID64: 1, X: 2000}, {SteamID64: 3}}},
+		{RoundNumber: 2, TeamName: "Team A", Side: common.TeamTerrorists, PlayerZones: []SetupPlayerZone{{SteamID64: 1, Y: 2000}, {SteamID64: 3}}},
+	}
+
+	tests := []struct {
+		name              string
+		shots             []*Shot
+		kills             []*Kill
+		setups            []*TeamSetup
+		expectedPrimary   constants.PlayerRole
+		expectedSecondary constants.PlayerRole
+	}{
+		{"awper", awpShots, nil, nil, constants.PlayerRoleAwper, ""},
+		{"entry", nil, openingKills, nil, constants.PlayerRoleEntry, ""},
+		{"lurker", nil, nil, lurkSetups, constants.PlayerRoleLurker, ""},
+		// Both scores are 1, the AWPer role comes first.
+		{"tie", awpShots, openingKills, nil, constants.PlayerRoleAwper, constants.PlayerRoleEntry},
+		{"no evidence", nil, nil, nil, "", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			teamA := &Team{Name: "Team A"}
+			teamB := &Team{Name: "Team B"}
+			match := &Match{
+				PlayersBySteamID: map[uint64]*Player{
+					1: {SteamID64: 1, Name: "foo", Team: teamA},
+					2: {SteamID64: 2, Name: "bar", Team: teamB},
+					3: {SteamID64: 3, Name: "baz", Team: teamA},
+				},
+				Rounds: []*Round{
+					{Number: 1, TeamAName: "Team A", TeamBName: "Team B", TeamASide: common.TeamTerrorists, TeamBSide: common.TeamCounterTerrorists},
+					{Number: 2, TeamAName: "Team A", TeamBName: "Team B", TeamASide: common.TeamTerrorists, TeamBSide: common.TeamCounterTerrorists},
+				},
+				Kills:      test.kills,
+				Shots:      test.shots,
+				TeamSetups: test.setups,
+			}
+
+			var evidence *PlayerRoleEvidence
+			for _, playerEvidence := range match.PlayerRoles() {
+				if playerEvidence.SteamID64 == 1 {
+					evidence = playerEvidence
+				}
+			}
+			if evidence == nil || evidence.RoundCount != 2 || evidence.Side != common.TeamTerrorists {
+				t.Fatalf("expected a T side evidence over 2 rounds, got %+v", evidence)
+			}
+			if evidence.PrimaryRole != test.expectedPrimary || evidence.SecondaryRole != test.expectedSecondary {
+				t.Errorf("expected roles %q / %q got %q / %q", test.expectedPrimary, test.expectedSecondary, evidence.PrimaryRole, evidence.SecondaryRole)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package api
+
+import (
+	"encoding/json"
+
+	"github.com/golang/geo/r3"
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
+)
+
+// Above this speed (units/s) between 2 position samples, we consider that the player has been teleported (respawn,
+// spectator position...) and the distance is not counted.
+const playerRoundMovementMaxSpeed = 2000.0
+
+type PlayerRoundMovement struct {
+	RoundNumber       int                `json:"roundNumber"`
+	PlayerName        string             `json:"playerName"`
+	PlayerSteamID64   uint64             `json:"playerSteamId"`
+	PlayerSide        common.Team        `json:"playerSide"`
+	PlayerTeamName    string             `json:"playerTeamName"`
+	TimeAliveSeconds  float64            `json:"timeAliveSeconds"`  // From the end of the freeze time until death or round end
+	DistanceTravelled float64            `json:"distanceTravelled"` // In game units
+	RotationCount     int                `json:"rotationCount"`     // Number of times the player moved from a bombsite to the other one
+	ZoneTimes         map[string]float64 `json:"zoneTimes"`         // Seconds spent in each map zone (nav mesh place name)
+	BombCarryTime     float64            `json:"bombCarryTime"`     // Seconds spent alive carrying the bomb, bots included
+	lastBombsite      string
+}
+
+type PlayerRoundMovementAlias PlayerRoundMovement
+
+type PlayerRoundMovementJSON struct {
+	*PlayerRoundMovementAlias
+	AverageSpeed float64 `json:"averageSpeed"`
+}
+
+func (movement *PlayerRoundMovement) MarshalJSON() ([]byte, error) {
+	return json.Marshal(PlayerRoundMovementJSON{
+		PlayerRoundMovementAlias: (*PlayerRoundMovementAlias)(movement),
+		AverageSpeed:             movement.AverageSpeed(),
+	})
+}
+
+func newPlayerRoundMovement(analyzer *Analyzer, player *common.Player) *PlayerRoundMovement {
+	return &PlayerRoundMovement{
+		RoundNumber:     analyzer.currentRound.Number,
+		PlayerName:      player.Name,
+		PlayerSteamID64: player.SteamID64,
+		PlayerSide:      player.Team,
+		PlayerTeamName:  analyzer.match.Team(player.Team).Name,
+		ZoneTimes:       make(map[string]float64),
+	}
+}
+
+// Average speed in units/s while the player was alive.
+func (movement *PlayerRoundMovement) AverageSpeed() float64 {
+	if movement.TimeAliveSeconds == 0 {
+		return 0
+	}
+
+	return movement.DistanceTravelled / movement.TimeAliveSeconds
+}
+
+func getPlayerPlaceName(player *common.Player) string {
+	entity := player.PlayerPawnEntity()
+	if entity == nil {
+		entity = player.Entity
+	}
+	if entity == nil {
+		return ""
+	}
+
+	if prop, exists := entity.PropertyValue("m_szLastPlaceName"); exists {
+		return prop.String()
+	}
+
+	return ""
+}
+
+func bombsiteFromPlaceName(placeName string) string {
+	switch placeName {
+	case "BombsiteA":
+		return "A"
+	case "BombsiteB":
+		return "B"
+	}
+
+	return ""
+}
+
+// Must be called from the FrameDone handler before the players position history rotation, previousPosition and
+// previousTick are the last known position and tick of the player.
+func (analyzer *Analyzer) updatePlayerRoundMovement(player *common.Player, previousPosition r3.Vector, previousTick int) {
+	round := analyzer.currentRound
+	currentTick := analyzer.currentTick()
+	if player.IsBot || !player.IsAlive() || round.FreezeTimeEndTick == -1 || round.EndTick > 0 {
+		return
+	}
+	if previousTick <= 0 || currentTick <= previousTick {
+		return
+	}
+
+	seconds := float64(currentTick-previousTick) * analyzer.parser.TickTime().Seconds()
+	distance := player.Position().Sub(previousPosition).Norm()
+	analyzer.playerRoundMovement(player).addSample(seconds, distance, getPlayerPlaceName(player))
+}
+
+// Returns the movement of the player for the current round, it's created if needed.
+func (analyzer *Analyzer) playerRoundMovement(player *common.Player) *PlayerRoundMovement {
+	match := analyzer.match
+	key := roundPlayerKey{roundNumber: analyzer.currentRound.Number, steamID64: player.SteamID64}
+	movement, exists := match.playerRoundMovementsByKey[key]
+	if !exists {
+		movement = newPlayerRoundMovement(analyzer, player)
+		match.playerRoundMovementsByKey[key] = movement
+		match.PlayerRoundMovements = append(match.PlayerRoundMovements, movement)
+	}
+
+	return movement
+}
+
+// Accumulates a position sample, seconds is the time elapsed since the previous sample and distance the distance
+// between both positions.
+func (movement *PlayerRoundMovement) addSample(seconds float64, distance float64, placeName string) {
+	if seconds <= 0 {
+		return
+	}
+
+	movement.TimeAliveSeconds += seconds
+	if distance/seconds <= playerRoundMovementMaxSpeed {
+		movement.DistanceTravelled += distance
+	}
+
+	if placeName == "" {
+		return
+	}
+	movement.ZoneTimes[placeName] += seconds
+
+	bombsite := bombsiteFromPlaceName(placeName)
+	if bombsite == "" {
+		return
+	}
+	if movement.lastBombsite != "" && movement.lastBombsite != bombsite {
+		movement.RotationCount++
+	}
+	movement.lastBombsite = bombsite
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestPlayerRoundMovement_AccumulatesTimeAndDistance(t *testing.T) {
+	movement := &PlayerRoundMovement{ZoneTimes: make(map[string]float64)}
+	movement.addSample(0.5, 100, "")
+	movement.addSample(0.5, 150, "")
+	// No time elapsed, e.g. 2 samples at the same tick.
+	movement.addSample(0, 50, "")
+
+	if movement.TimeAliveSeconds != 1 {
+		t.Errorf("expected 1 second alive got %f", movement.TimeAliveSeconds)
+	}
+	if movement.DistanceTravelled != 250 {
+		t.Errorf("expected a distance of 250 got %f", movement.DistanceTravelled)
+	}
+	if movement.AverageSpeed() != 250 {
+		t.Errorf("expected an average speed of 250 got %f", movement.AverageSpeed())
+	}
+
+	data, err := json.Marshal(movement)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var values map[string]any
+	if err := json.Unmarshal(data, &values); err != nil {
+		t.Fatal(err)
+	}
+	if values["averageSpeed"] != 250.0 {
+		t.Errorf("expected the average speed to be exported got %v", values["averageSpeed"])
+	}
+}
+
+func TestPlayerRoundMovement_IgnoresTeleports(t *testing.T) {
+	movement := &PlayerRoundMovement{ZoneTimes: make(map[string]float64)}
+	movement.addSample(1.0/64, 10, "")
+	// ~64000 units/s, the player has been teleported.
+	movement.addSample(1.0/64, 1000, "")
+
+	if movement.DistanceTravelled != 10 {
+		t.Errorf("expected the teleport distance to be ignored got %f", movement.DistanceTravelled)
+	}
+	if math.Abs(movement.TimeAliveSeconds-2.0/64) > 1e-9 {
+		t.Errorf("expected the teleport sample to count as time alive got %f", movement.TimeAliveSeconds)
+	}
+	if (&PlayerRoundMovement{}).AverageSpeed() != 0 {
+		t.Errorf("expected an average speed of 0 without time alive")
+	}
+}
+
+func TestPlayerRoundMovement_ZoneTimesAndRotations(t *testing.T) {
+	movement := &PlayerRoundMovement{ZoneTimes: make(map[string]float64)}
+	for _, placeName := range []string{"BombsiteA", "BombsiteA", "Middle", "BombsiteA", "Middle", "BombsiteB", "", "BombsiteA"} {
+		movement.addSample(1, 10, placeName)
+	}
+
+	expectedZoneTimes := map[string]float64{"BombsiteA": 4, "Middle": 2, "BombsiteB": 1}
+	if len(movement.ZoneTimes) != len(expectedZoneTimes) {
+		t.Fatalf("expected zone times %v got %v", expectedZoneTimes, movement.ZoneTimes)
+	}
+	for zone, seconds := range expectedZoneTimes {
+		if movement.ZoneTimes[zone] != seconds {
+			t.Errorf("expected %f seconds in %s got %f", seconds, zone, movement.ZoneTimes[zone])
+		}
+	}
+	// Going back to the same bombsite through another zone is not a rotation.
+	if movement.RotationCount != 2 {
+		t.Errorf("expected 2 rotations got %d", movement.RotationCount)
+	}
+	if movement.TimeAliveSeconds != 8 {
+		t.Errorf("expected 8 seconds alive got %f", movement.TimeAliveSeconds)
+	}
+}
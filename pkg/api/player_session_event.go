@@ -0,0 +1,71 @@
+package api
+
+import (
+	"github.com/akiver/cs-demo-analyzer/pkg/api/constants"
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
+)
+
+type PlayerSessionEvent struct {
+	Frame           int                              `json:"frame"`
+	Tick            int                              `json:"tick"`
+	RoundNumber     int                              `json:"roundNumber"`
+	Type            constants.PlayerSessionEventType `json:"type"`
+	PlayerName      string                           `json:"playerName"`
+	PlayerSteamID64 uint64                           `json:"playerSteamId"`
+	OldSide         common.Team                      `json:"oldSide"` // Team changes only
+	NewSide         common.Team                      `json:"newSide"`
+	BotName         string                           `json:"botName"` // Bot takeovers only, the bot controlled by the player
+}
+
+func newPlayerSessionEvent(analyzer *Analyzer, eventType constants.PlayerSessionEventType, player *common.Player) *PlayerSessionEvent {
+	return &PlayerSessionEvent{
+		Frame:           analyzer.parser.CurrentFrame(),
+		Tick:            analyzer.currentTick(),
+		RoundNumber:     analyzer.currentRound.Number,
+		Type:            eventType,
+		PlayerName:      player.Name,
+		PlayerSteamID64: player.SteamID64,
+		OldSide:         player.Team,
+		NewSide:         player.Team,
+	}
+}
+
+func (analyzer *Analyzer) registerPlayerSessionEvent(eventType constants.PlayerSessionEventType, player *common.Player) *PlayerSessionEvent {
+	if player == nil || player.IsBot || player.SteamID64 == 0 {
+		return nil
+	}
+
+	if eventType == constants.PlayerSessionEventTypeDisconnect {
+		analyzer.disconnectedPlayers[player.SteamID64] = true
+	} else if eventType == constants.PlayerSessionEventTypeConnect && analyzer.disconnectedPlayers[player.SteamID64] {
+		eventType = constants.PlayerSessionEventTypeReconnect
+		delete(analyzer.disconnectedPlayers, player.SteamID64)
+	}
+
+	event := newPlayerSessionEvent(analyzer, eventType, player)
+	analyzer.match.PlayerSessionEvents = append(analyzer.match.PlayerSessionEvents, event)
+
+	return event
+}
+
+// Mark connected players on a team as having played the current round, it must be called while the round is live.
+func (analyzer *Analyzer) updatePlayersRoundPlayed() {
+	round := analyzer.currentRound
+	if round.FreezeTimeEndTick == -1 || round.EndTick > 0 {
+		return
+	}
+
+	match := analyzer.match
+	connectedPlayerCount := 0
+	for _, player := range analyzer.parser.GameState().Participants().Playing() {
+		if !player.IsConnected {
+			continue
+		}
+		connectedPlayerCount++
+		if player.IsBot || player.SteamID64 == 0 {
+			continue
+		}
+		match.playerRoundsPlayed[roundPlayerKey{roundNumber: round.Number, steamID64: player.SteamID64}] = true
+	}
+	match.connectedPlayerCountByRound[round.Number] = max(match.connectedPlayerCountByRound[round.Number], connectedPlayerCount)
+}
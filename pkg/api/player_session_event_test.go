@@ -0,0 +1,35 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
+)
+
+func TestPlayerRatesPerRoundPlayed(t *testing.T) {
+	match := &Match{
+		Rounds: []*Round{{Number: 1}, {Number: 2}, {Number: 3}, {Number: 4}},
+		Kills: []*Kill{
+			{KillerSteamID64: 1, KillerSide: common.TeamTerrorists, VictimSteamID64: 2, VictimSide: common.TeamCounterTerrorists},
+			{KillerSteamID64: 1, KillerSide: common.TeamTerrorists, VictimSteamID64: 3, VictimSide: common.TeamCounterTerrorists},
+		},
+		playerRoundsPlayed: map[roundPlayerKey]bool{{roundNumber: 3, steamID64: 1}: true, {roundNumber: 4, steamID64: 1}: true},
+	}
+	substitute := &Player{match: match, SteamID64: 1}
+	if substitute.RoundsPlayedCount() != 2 {
+		t.Fatalf("expected 2 rounds played, got %d", substitute.RoundsPlayedCount())
+	}
+	if substitute.AverageKillPerRoundPlayed() != 1 {
+		t.Fatalf("expected 1 kill per round played, got %f", substitute.AverageKillPerRoundPlayed())
+	}
+	// Existing rate stats still use the match rounds count.
+	if substitute.AverageKillPerRound() != 0.5 {
+		t.Fatalf("expected 0.5 kill per round, got %f", substitute.AverageKillPerRound())
+	}
+
+	// The presence of the player has not been detected, it fallbacks to the match rounds count.
+	povPlayer := &Player{match: match, SteamID64: 2}
+	if povPlayer.playedRoundCount() != 4 {
+		t.Fatalf("expected 4 rounds, got %d", povPlayer.playedRoundCount())
+	}
+}
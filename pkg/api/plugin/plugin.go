@@ -0,0 +1,37 @@
+// Package plugin is the extension point of the analysis, plugins register their own parser event handlers and fill
+// custom tables that are attached to the match and exported with it.
+package plugin
+
+import (
+	"log/slog"
+
+	dem "github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs"
+)
+
+// A plugin is registered once per analysis, see api.AnalyzeDemoOptions.Plugins.
+// A new instance should be used for each analysis if the plugin keeps a state.
+type Plugin interface {
+	// Unique name of the plugin (lowercase letters, digits and underscores), it prefixes its tables file name.
+	Name() string
+	// Called before the parsing starts, event handlers and tables must be created here.
+	Register(ctx Context)
+}
+
+// Gives access to the parser and the analysis state, it must not be used after the analysis.
+type Context interface {
+	// Handlers are called after the analyzer ones, see Parser.RegisterEventHandler.
+	RegisterEventHandler(handler any)
+	// The parser may be used to access the game state or to register net message handlers.
+	Parser() dem.Parser
+	// False during the warmup and before the match start, data collected at this time are usually ignored.
+	MatchStarted() bool
+	CurrentFrame() int
+	CurrentTick() int
+	CurrentRoundNumber() int
+	// Logger of the analysis, it has a "plugin" attribute.
+	Logger() *slog.Logger
+	// Creates a table attached to the match, name must be unique for the plugin (lowercase letters, digits and
+	// underscores).
+	// The analysis fails with an InvalidArgument error if the name is not valid.
+	NewTable(name string, columns ...string) *Table
+}
@@ -0,0 +1,47 @@
+package plugin
+
+import "fmt"
+
+// Custom data collected by a plugin, exported as <prefix>_plugin_<plugin>_<table>.csv by the CSV export and in the
+// "customTables" array of the JSON export.
+// Rows of rounds that are restarted and rows collected before a match restart are removed like built-in data.
+type Table struct {
+	Plugin  string   `json:"plugin"`
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"` // Without the frame, tick and round columns
+	Rows    []*Row   `json:"rows"`
+	context Context
+}
+
+type Row struct {
+	Frame       int   `json:"frame"`
+	Tick        int   `json:"tick"`
+	RoundNumber int   `json:"roundNumber"`
+	Values      []any `json:"values"`
+}
+
+// Used by the analyzer to create the tables requested with Context.NewTable.
+func NewTable(ctx Context, pluginName string, name string, columns []string) *Table {
+	return &Table{
+		Plugin:  pluginName,
+		Name:    name,
+		Columns: columns,
+		Rows:    []*Row{},
+		context: ctx,
+	}
+}
+
+// Adds a row with the current frame, tick and round number.
+// Values are usually strings, numbers or booleans, it panics if the number of values doesn't match the columns.
+func (table *Table) AddRow(values ...any) {
+	if len(values) != len(table.Columns) {
+		panic(fmt.Sprintf("table %s_%s has %d columns but %d values provided", table.Plugin, table.Name, len(table.Columns), len(values)))
+	}
+
+	table.Rows = append(table.Rows, &Row{
+		Frame:       table.context.CurrentFrame(),
+		Tick:        table.context.CurrentTick(),
+		RoundNumber: table.context.CurrentRoundNumber(),
+		Values:      values,
+	})
+}
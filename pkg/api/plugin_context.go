@@ -0,0 +1,90 @@
+package api
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+
+	"github.com/akiver/cs-demo-analyzer/pkg/api/plugin"
+	dem "github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs"
+)
+
+// Plugins, tables and metrics names are used in the exported files name.
+var tableNameRegexp = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+type pluginContext struct {
+	analyzer   *Analyzer
+	pluginName string
+	logger     *slog.Logger
+}
+
+func (ctx *pluginContext) RegisterEventHandler(handler any) {
+	ctx.analyzer.parser.RegisterEventHandler(handler)
+}
+
+func (ctx *pluginContext) Parser() dem.Parser {
+	return ctx.analyzer.parser
+}
+
+func (ctx *pluginContext) MatchStarted() bool {
+	return ctx.analyzer.matchStarted()
+}
+
+func (ctx *pluginContext) CurrentFrame() int {
+	return ctx.analyzer.parser.CurrentFrame()
+}
+
+func (ctx *pluginContext) CurrentTick() int {
+	return ctx.analyzer.currentTick()
+}
+
+func (ctx *pluginContext) CurrentRoundNumber() int {
+	return ctx.analyzer.currentRound.Number
+}
+
+func (ctx *pluginContext) Logger() *slog.Logger {
+	return ctx.logger
+}
+
+func (ctx *pluginContext) NewTable(name string, columns ...string) *plugin.Table {
+	if !tableNameRegexp.MatchString(name) {
+		panic(abortAnalysis{newError(ErrorCodeInvalidArgument, fmt.Sprintf("invalid table name %q for plugin %s", name, ctx.pluginName), nil)})
+	}
+
+	// Tables are exported as <plugin>_<table>, e.g. the table b_c of the plugin a and the table c of the plugin a_b
+	// would be written in the same file.
+	match := ctx.analyzer.match
+	for _, table := range match.CustomTables {
+		if table.Plugin+"_"+table.Name == ctx.pluginName+"_"+name {
+			panic(abortAnalysis{newError(ErrorCodeInvalidArgument, fmt.Sprintf("table %q of plugin %s conflicts with the table %q of plugin %s", name, ctx.pluginName, table.Name, table.Plugin), nil)})
+		}
+	}
+
+	table := plugin.NewTable(ctx, ctx.pluginName, name, columns)
+	match.CustomTables = append(match.CustomTables, table)
+
+	return table
+}
+
+// Must be called once the source specific handlers are registered, matchStarted is set by them.
+func (analyzer *Analyzer) registerPlugins(plugins []plugin.Plugin) error {
+	names := make(map[string]bool)
+	for _, p := range plugins {
+		name := p.Name()
+		if !tableNameRegexp.MatchString(name) {
+			return newError(ErrorCodeInvalidArgument, fmt.Sprintf("invalid plugin name %q", name), nil)
+		}
+		if names[name] {
+			return newError(ErrorCodeInvalidArgument, fmt.Sprintf("plugin %s registered multiple times", name), nil)
+		}
+		names[name] = true
+
+		p.Register(&pluginContext{
+			analyzer:   analyzer,
+			pluginName: name,
+			logger:     analyzer.logger.With("plugin", name),
+		})
+	}
+
+	return nil
+}
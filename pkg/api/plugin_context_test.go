@@ -0,0 +1,155 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/akiver/cs-demo-analyzer/pkg/api/plugin"
+	dem "github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs"
+)
+
+type testPlugin struct {
+	name     string
+	register func(ctx plugin.Context)
+}
+
+func (p *testPlugin) Name() string {
+	return p.name
+}
+
+func (p *testPlugin) Register(ctx plugin.Context) {
+	if p.register != nil {
+		p.register(ctx)
+	}
+}
+
+func newPluginTestAnalyzer() *Analyzer {
+	return &Analyzer{
+		// The parser reads the beginning of the stream when it is created.
+		parser:       dem.NewParser(bytes.NewReader(make([]byte, 1024))),
+		match:        &Match{CustomTables: []*plugin.Table{}},
+		currentRound: &Round{Number: 1},
+		matchStarted: func() bool { return true },
+		logger:       slog.New(slog.DiscardHandler),
+	}
+}
+
+func TestRegisterPlugins_Tables(t *testing.T) {
+	analyzer := newPluginTestAnalyzer()
+	var table *plugin.Table
+	err := analyzer.registerPlugins([]plugin.Plugin{&testPlugin{
+		name: "test",
+		register: func(ctx plugin.Context) {
+			table = ctx.NewTable("events", "steamid", "value")
+		},
+	}})
+	if err != nil {
+		t.Fatalf("expected no error got %v", err)
+	}
+
+	table.AddRow(uint64(76561198000000001), 1.5)
+	analyzer.currentRound.Number = 2
+	table.AddRow(uint64(76561198000000002), true)
+
+	match := analyzer.match
+	if len(match.CustomTables) != 1 || match.CustomTables[0] != table {
+		t.Fatalf("expected the table to be attached to the match got %v", match.CustomTables)
+	}
+	if table.Plugin != "test" || table.Name != "events" {
+		t.Errorf("expected table test_events got %s_%s", table.Plugin, table.Name)
+	}
+	if len(table.Rows) != 2 || table.Rows[1].RoundNumber != 2 {
+		t.Fatalf("expected 2 rows with the current round number got %v", table.Rows)
+	}
+
+	match.resetRound(2)
+	if len(table.Rows) != 1 || table.Rows[0].RoundNumber != 1 {
+		t.Fatalf("expected rows of the round 2 to be removed got %v", table.Rows)
+	}
+
+	match.reset()
+	if len(match.CustomTables) != 1 || len(table.Rows) != 0 {
+		t.Fatalf("expected the table to be kept without rows got %d tables and %d rows", len(match.CustomTables), len(table.Rows))
+	}
+}
+
+func TestRegisterPlugins_InvalidNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		plugins []plugin.Plugin
+	}{
+		{"invalid plugin name", []plugin.Plugin{&testPlugin{name: "Fun Data"}}},
+		{"duplicated plugin", []plugin.Plugin{&testPlugin{name: "test"}, &testPlugin{name: "test"}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := newPluginTestAnalyzer().registerPlugins(test.plugins)
+			if !errors.Is(err, ErrInvalidArgument) {
+				t.Errorf("expected an invalid argument error got %v", err)
+			}
+		})
+	}
+}
+
+func TestRegisterPlugins_InvalidTableName(t *testing.T) {
+	register := func() (err error) {
+		defer recoverAnalysisPanic(&err)
+		return newPluginTestAnalyzer().registerPlugins([]plugin.Plugin{&testPlugin{
+			name: "test",
+			register: func(ctx plugin.Context) {
+				ctx.NewTable("events")
+				ctx.NewTable("events")
+			},
+		}})
+	}
+
+	err := register()
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("expected an invalid argument error got %v", err)
+	}
+
+	// Both tables would be exported as _plugin_foo_bar_events.csv.
+	err = func() (err error) {
+		defer recoverAnalysisPanic(&err)
+		return newPluginTestAnalyzer().registerPlugins([]plugin.Plugin{
+			&testPlugin{name: "foo", register: func(ctx plugin.Context) { ctx.NewTable("bar_events") }},
+			&testPlugin{name: "foo_bar", register: func(ctx plugin.Context) { ctx.NewTable("events") }},
+		})
+	}()
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("expected an invalid argument error for conflicting table files got %v", err)
+	}
+}
+
+func TestWriteCustomTable(t *testing.T) {
+	analyzer := newPluginTestAnalyzer()
+	match := analyzer.match
+	match.Checksum = "checksum"
+	err := analyzer.registerPlugins([]plugin.Plugin{&testPlugin{
+		name: "test",
+		register: func(ctx plugin.Context) {
+			ctx.NewTable("events", "name", "count").AddRow("foo", 3)
+		},
+	}})
+	if err != nil {
+		t.Fatalf("expected no error got %v", err)
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "demo")
+	writeCustomTable(match, match.CustomTables[0], outputPath)
+
+	content, err := os.ReadFile(outputPath + "_plugin_test_events.csv")
+	if err != nil {
+		t.Fatalf("expected the table file to be written got %v", err)
+	}
+	expected := "frame,tick,round,name,count,match checksum\n0,0,1,foo,3,checksum\n"
+	if strings.ReplaceAll(string(content), "\r\n", "\n") != expected {
+		t.Errorf("expected %q got %q", expected, string(content))
+	}
+}
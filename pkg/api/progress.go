@@ -0,0 +1,56 @@
+package api
+
+import (
+	"io"
+	"os"
+)
+
+// Minimum progress difference between 2 OnProgress calls, calls also happen when a new round starts.
+const progressReportStep = 0.01
+
+type progressReporter struct {
+	onProgress   func(fraction float64, round int)
+	fraction     func() float64
+	lastFraction float64
+	lastRound    int
+}
+
+func (reporter *progressReporter) report(fraction float64, round int) {
+	fraction = min(1, max(reporter.lastFraction, fraction))
+	reporter.lastFraction = fraction
+	reporter.lastRound = round
+	reporter.onProgress(fraction, round)
+}
+
+func (reporter *progressReporter) update(round int) {
+	if reporter.onProgress == nil {
+		return
+	}
+
+	fraction := reporter.fraction()
+	if fraction-reporter.lastFraction >= progressReportStep || round != reporter.lastRound {
+		reporter.report(fraction, round)
+	}
+}
+
+func (reporter *progressReporter) complete(round int) {
+	if reporter.onProgress != nil {
+		reporter.report(1, round)
+	}
+}
+
+// Returns the fraction of the file already read, it's used when the parser can't report its progress (CS2 demos
+// don't have the frames count in their header).
+func fileReadFraction(file *os.File, size int64) func() float64 {
+	return func() float64 {
+		if size <= 0 {
+			return 0
+		}
+		position, err := file.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return 0
+		}
+
+		return float64(position) / float64(size)
+	}
+}
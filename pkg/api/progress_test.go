@@ -0,0 +1,49 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestProgressReporter_ThrottlesAndCompletes(t *testing.T) {
+	var fractions []float64
+	var fraction float64
+	reporter := &progressReporter{
+		onProgress: func(value float64, round int) {
+			fractions = append(fractions, value)
+		},
+		fraction: func() float64 {
+			return fraction
+		},
+	}
+
+	for _, value := range []float64{0.001, 0.005, 0.012, 0.015, 0.5, 0.4} {
+		fraction = value
+		reporter.update(0)
+	}
+	// A new round is always reported.
+	reporter.update(1)
+	reporter.complete(1)
+
+	expected := []float64{0.012, 0.5, 0.5, 1}
+	if len(fractions) != len(expected) {
+		t.Fatalf("expected %v got %v", expected, fractions)
+	}
+	for index, value := range expected {
+		if fractions[index] != value {
+			t.Fatalf("expected %v got %v", expected, fractions)
+		}
+	}
+}
+
+func TestAnalyzeDemoFromReaderContext_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := AnalyzeDemoFromReaderContext(ctx, bytes.NewReader(nil), "demo.dem", AnalyzeDemoOptions{})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
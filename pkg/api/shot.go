@@ -1,7 +1,6 @@
 package api
 
 import (
-	"fmt"
 	stdmath "math"
 
 	"github.com/akiver/cs-demo-analyzer/pkg/api/constants"
@@ -40,6 +39,7 @@ type Shot struct {
 	ViewPunchAngleX        float64              `json:"viewPunchAngleX"`
 	ViewPunchAngleY        float64              `json:"viewPunchAngleY"`
 	IsPlayerRunning        bool                 `json:"isPlayerRunning"`
+	IsPlayerAirborne       bool                 `json:"isPlayerAirborne"`
 }
 
 func newShot(analyzer *Analyzer, event events.WeaponFire) *Shot {
@@ -92,7 +92,7 @@ func newShot(analyzer *Analyzer, event events.WeaponFire) *Shot {
 			isPlayerRunning = true
 		}
 	} else {
-		fmt.Printf("Error: Weapon '%s' not found in accurate speed constants\n", weaponName)
+		analyzer.warn(constants.WarningKindUnknownWeapon, "Weapon %q not found in accurate speed constants", weaponName)
 	}
 
 	return &Shot{
@@ -122,5 +122,6 @@ func newShot(analyzer *Analyzer, event events.WeaponFire) *Shot {
 		ViewPunchAngleX:        viewPunchAngle.X,
 		ViewPunchAngleY:        viewPunchAngle.Y,
 		IsPlayerRunning:        isPlayerRunning,
+		IsPlayerAirborne:       shooter.IsAirborne(),
 	}
 }
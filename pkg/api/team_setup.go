@@ -0,0 +1,223 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
+)
+
+// Default offsets in seconds after the end of the freeze time at which team setups are captured.
+var DefaultSetupSnapshotOffsets = []float64{10, 20, 40}
+
+type SetupPlayerZone struct {
+	SteamID64 uint64  `json:"steamId"`
+	Name      string  `json:"name"`
+	Zone      string  `json:"zone"`
+	X         float64 `json:"x"`
+	Y         float64 `json:"y"`
+	Z         float64 `json:"z"`
+}
+
+type TeamSetup struct {
+	Frame            int               `json:"frame"`
+	Tick             int               `json:"tick"`
+	RoundNumber      int               `json:"roundNumber"`
+	OffsetSeconds    float64           `json:"offsetSeconds"`
+	TeamName         string            `json:"teamName"`
+	Side             common.Team       `json:"side"`
+	AlivePlayerCount int               `json:"alivePlayerCount"`
+	Signature        string            `json:"signature"` // Number of alive players per zone, e.g. "BombsiteA:2,BombsiteB:2,Middle:1"
+	PlayerZones      []SetupPlayerZone `json:"playerZones"`
+}
+
+type SetupCluster struct {
+	TeamName      string      `json:"teamName"`
+	Side          common.Team `json:"side"`
+	OffsetSeconds float64     `json:"offsetSeconds"`
+	Signature     string      `json:"signature"`
+	RoundCount    int         `json:"roundCount"`
+	WinCount      int         `json:"winCount"`
+	Frequency     float32     `json:"frequency"` // % of the team's rounds on this side where the setup was used at this offset
+	WinRate       float32     `json:"winRate"`
+}
+
+func computeSetupSignature(zones []string) string {
+	countByZone := make(map[string]int)
+	for _, zone := range zones {
+		if zone == "" {
+			zone = "Unknown"
+		}
+		countByZone[zone]++
+	}
+
+	names := make([]string, 0, len(countByZone))
+	for zone := range countByZone {
+		names = append(names, zone)
+	}
+	sort.Slice(names, func(i int, j int) bool {
+		if countByZone[names[i]] != countByZone[names[j]] {
+			return countByZone[names[i]] > countByZone[names[j]]
+		}
+
+		return names[i] < names[j]
+	})
+
+	parts := make([]string, 0, len(names))
+	for _, zone := range names {
+		parts = append(parts, fmt.Sprintf("%s:%d", zone, countByZone[zone]))
+	}
+
+	return strings.Join(parts, ",")
+}
+
+func newTeamSetup(analyzer *Analyzer, team *Team, offsetSeconds float64) *TeamSetup {
+	setup := &TeamSetup{
+		Frame:         analyzer.parser.CurrentFrame(),
+		Tick:          analyzer.currentTick(),
+		RoundNumber:   analyzer.currentRound.Number,
+		OffsetSeconds: offsetSeconds,
+		TeamName:      team.Name,
+		Side:          *team.CurrentSide,
+		PlayerZones:   []SetupPlayerZone{},
+	}
+
+	var zones []string
+	for _, player := range analyzer.parser.GameState().Participants().Playing() {
+		if player.Team != *team.CurrentSide || !player.IsAlive() {
+			continue
+		}
+
+		zone := getPlayerPlaceName(player)
+		position := player.Position()
+		setup.PlayerZones = append(setup.PlayerZones, SetupPlayerZone{
+			SteamID64: player.SteamID64,
+			Name:      player.Name,
+			Zone:      zone,
+			X:         position.X,
+			Y:         position.Y,
+			Z:         position.Z,
+		})
+		zones = append(zones, zone)
+	}
+	sort.Slice(setup.PlayerZones, func(i int, j int) bool {
+		return setup.PlayerZones[i].SteamID64 < setup.PlayerZones[j].SteamID64
+	})
+
+	setup.AlivePlayerCount = len(setup.PlayerZones)
+	setup.Signature = computeSetupSignature(zones)
+
+	return setup
+}
+
+func sortedSetupSnapshotOffsets(offsets []float64) []float64 {
+	if offsets == nil {
+		offsets = DefaultSetupSnapshotOffsets
+	}
+
+	sorted := make([]float64, 0, len(offsets))
+	for _, offset := range offsets {
+		if offset >= 0 {
+			sorted = append(sorted, offset)
+		}
+	}
+	sort.Float64s(sorted)
+
+	return sorted
+}
+
+// Capture teams setup once the current round reached the next configured offset after the end of the freeze time.
+func (analyzer *Analyzer) takeTeamSetupSnapshots() {
+	round := analyzer.currentRound
+	if round.FreezeTimeEndTick == -1 || round.EndTick > 0 {
+		return
+	}
+
+	for analyzer.setupSnapshotIndex < len(analyzer.setupSnapshotOffsets) {
+		offset := analyzer.setupSnapshotOffsets[analyzer.setupSnapshotIndex]
+		if !analyzer.secondsHasPassedSinceTick(offset, round.FreezeTimeEndTick) {
+			return
+		}
+
+		match := analyzer.match
+		match.TeamSetups = append(match.TeamSetups, newTeamSetup(analyzer, match.TeamA, offset))
+		match.TeamSetups = append(match.TeamSetups, newTeamSetup(analyzer, match.TeamB, offset))
+		analyzer.setupSnapshotIndex++
+	}
+}
+
+type setupClusterKey struct {
+	teamName      string
+	side          common.Team
+	offsetSeconds float64
+	signature     string
+}
+
+type setupRoundsKey struct {
+	teamName      string
+	side          common.Team
+	offsetSeconds float64
+}
+
+// This returns recurring setups grouped by team, side, offset and signature.
+func (match *Match) SetupClusters() []*SetupCluster {
+	winnerByRound := make(map[int]string)
+	for _, round := range match.Rounds {
+		winnerByRound[round.Number] = round.WinnerName
+	}
+
+	clustersByKey := make(map[setupClusterKey]*SetupCluster)
+	roundCountByKey := make(map[setupRoundsKey]int)
+	for _, setup := range match.TeamSetups {
+		if setup.AlivePlayerCount == 0 {
+			continue
+		}
+
+		roundCountByKey[setupRoundsKey{teamName: setup.TeamName, side: setup.Side, offsetSeconds: setup.OffsetSeconds}]++
+		key := setupClusterKey{teamName: setup.TeamName, side: setup.Side, offsetSeconds: setup.OffsetSeconds, signature: setup.Signature}
+		cluster, exists := clustersByKey[key]
+		if !exists {
+			cluster = &SetupCluster{
+				TeamName:      setup.TeamName,
+				Side:          setup.Side,
+				OffsetSeconds: setup.OffsetSeconds,
+				Signature:     setup.Signature,
+			}
+			clustersByKey[key] = cluster
+		}
+
+		cluster.RoundCount++
+		if winnerByRound[setup.RoundNumber] == setup.TeamName {
+			cluster.WinCount++
+		}
+	}
+
+	clusters := make([]*SetupCluster, 0, len(clustersByKey))
+	for _, cluster := range clustersByKey {
+		roundCount := roundCountByKey[setupRoundsKey{teamName: cluster.TeamName, side: cluster.Side, offsetSeconds: cluster.OffsetSeconds}]
+		cluster.Frequency = float32(cluster.RoundCount) / float32(roundCount) * 100
+		cluster.WinRate = float32(cluster.WinCount) / float32(cluster.RoundCount) * 100
+		clusters = append(clusters, cluster)
+	}
+
+	sort.Slice(clusters, func(i int, j int) bool {
+		a, b := clusters[i], clusters[j]
+		if a.TeamName != b.TeamName {
+			return a.TeamName < b.TeamName
+		}
+		if a.Side != b.Side {
+			return a.Side < b.Side
+		}
+		if a.OffsetSeconds != b.OffsetSeconds {
+			return a.OffsetSeconds < b.OffsetSeconds
+		}
+		if a.RoundCount != b.RoundCount {
+			return a.RoundCount > b.RoundCount
+		}
+
+		return a.Signature < b.Signature
+	})
+
+	return clusters
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
+)
+
+func TestComputeSetupSignature_GroupsZonesByCount(t *testing.T) {
+	signature := computeSetupSignature([]string{"Middle", "BombsiteB", "BombsiteA", "BombsiteB", "BombsiteA"})
+
+	if signature != "BombsiteA:2,BombsiteB:2,Middle:1" {
+		t.Fatalf("unexpected signature %q", signature)
+	}
+}
+
+func TestSetupClusters_ComputesFrequencyAndWinRate(t *testing.T) {
+	match := &Match{
+		Rounds: []*Round{
+			{Number: 1, WinnerName: "Team A"},
+			{Number: 2, WinnerName: "Team B"},
+			{Number: 3, WinnerName: "Team A"},
+			{Number: 4, WinnerName: "Team A"},
+		},
+		TeamSetups: []*TeamSetup{
+			{RoundNumber: 1, OffsetSeconds: 20, TeamName: "Team A", Side: common.TeamCounterTerrorists, AlivePlayerCount: 5, Signature: "BombsiteA:2,BombsiteB:2,Middle:1"},
+			{RoundNumber: 2, OffsetSeconds: 20, TeamName: "Team A", Side: common.TeamCounterTerrorists, AlivePlayerCount: 5, Signature: "BombsiteA:2,BombsiteB:2,Middle:1"},
+			{RoundNumber: 3, OffsetSeconds: 20, TeamName: "Team A", Side: common.TeamCounterTerrorists, AlivePlayerCount: 5, Signature: "BombsiteB:3,BombsiteA:1,Middle:1"},
+			{RoundNumber: 4, OffsetSeconds: 20, TeamName: "Team A", Side: common.TeamCounterTerrorists, AlivePlayerCount: 0, Signature: ""},
+		},
+	}
+
+	clusters := match.SetupClusters()
+
+	if len(clusters) != 2 {
+		t.Fatalf("expected 2 clusters, got %d", len(clusters))
+	}
+	cluster := clusters[0]
+	if cluster.Signature != "BombsiteA:2,BombsiteB:2,Middle:1" || cluster.RoundCount != 2 || cluster.WinCount != 1 {
+		t.Fatalf("unexpected most frequent cluster %+v", cluster)
+	}
+	if cluster.WinRate != 50 {
+		t.Fatalf("expected 50%% win rate, got %f", cluster.WinRate)
+	}
+	if clusters[1].Frequency < 33.3 || clusters[1].Frequency > 33.4 {
+		t.Fatalf("expected ~33.3%% frequency, got %f", clusters[1].Frequency)
+	}
+}
@@ -0,0 +1,205 @@
+package api
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/akiver/cs-demo-analyzer/pkg/api/constants"
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
+	st "github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/sendtables"
+)
+
+// Chat commands used by MatchZy and eBot (and most server plugins), prefixed with "!" or ".".
+var timeoutChatCommands = map[string]constants.TimeoutType{
+	"tac":       constants.TimeoutTypeTactical,
+	"tactical":  constants.TimeoutTypeTactical,
+	"timeout":   constants.TimeoutTypeTactical,
+	"tech":      constants.TimeoutTypeTechnical,
+	"technical": constants.TimeoutTypeTechnical,
+	"pause":     constants.TimeoutTypePause,
+	"p":         constants.TimeoutTypePause,
+}
+
+// "ready" and "r" are not unpause commands, players type them to ready up during the warmup.
+var unpauseChatCommands = []string{"unpause", "up"}
+
+type Timeout struct {
+	Frame              int                     `json:"frame"`
+	Tick               int                     `json:"tick"`
+	EndFrame           int                     `json:"endFrame"` // 0 if the end has not been detected
+	EndTick            int                     `json:"endTick"`
+	RoundNumber        int                     `json:"roundNumber"`
+	Type               constants.TimeoutType   `json:"type"`
+	Source             constants.TimeoutSource `json:"source"`
+	TeamName           string                  `json:"teamName"` // Team that called the timeout, may be empty for technical timeouts and pauses
+	Side               common.Team             `json:"side"`
+	RequesterName      string                  `json:"requesterName"` // Player who requested the timeout in the chat, if any
+	RequesterSteamID64 uint64                  `json:"requesterSteamId"`
+}
+
+func newTimeout(analyzer *Analyzer, timeoutType constants.TimeoutType, side common.Team) *Timeout {
+	timeout := &Timeout{
+		Frame:       analyzer.parser.CurrentFrame(),
+		Tick:        analyzer.currentTick(),
+		RoundNumber: analyzer.currentRound.Number,
+		Type:        timeoutType,
+		Source:      constants.TimeoutSourceGameRules,
+		Side:        side,
+	}
+	if side == common.TeamTerrorists || side == common.TeamCounterTerrorists {
+		timeout.TeamName = analyzer.match.Team(side).Name
+	}
+
+	return timeout
+}
+
+func (timeout *Timeout) DurationSeconds(tickRate float64) float64 {
+	if timeout.EndTick == 0 || tickRate <= 0 {
+		return 0
+	}
+
+	return float64(timeout.EndTick-timeout.Tick) / tickRate
+}
+
+// Register game rules props update handlers that open and close timeouts.
+// Props are prefixed with "cs_gamerules_data." in CSGO demos and "m_pGameRules." in CS2 demos.
+func (analyzer *Analyzer) registerTimeoutHandlers(entity st.Entity) {
+	activeTimeouts := make(map[string]*Timeout)
+	watch := func(propName string, key string, timeoutType constants.TimeoutType, side common.Team) {
+		prop := entity.Property("cs_gamerules_data." + propName)
+		if prop == nil {
+			prop = entity.Property("m_pGameRules." + propName)
+		}
+		if prop == nil {
+			return
+		}
+		analyzer.match.gameRulesTimeoutTypes[timeoutType] = true
+
+		prop.OnUpdate(func(val st.PropertyValue) {
+			activeTimeout := activeTimeouts[key]
+			if val.BoolVal() {
+				if activeTimeout != nil || !analyzer.matchStarted() {
+					return
+				}
+				timeout := newTimeout(analyzer, timeoutType, side)
+				analyzer.match.Timeouts = append(analyzer.match.Timeouts, timeout)
+				activeTimeouts[key] = timeout
+				return
+			}
+
+			if activeTimeout == nil {
+				return
+			}
+			activeTimeout.EndFrame = analyzer.parser.CurrentFrame()
+			activeTimeout.EndTick = analyzer.currentTick()
+			delete(activeTimeouts, key)
+		})
+	}
+
+	watch("m_bTerroristTimeOutActive", "t", constants.TimeoutTypeTactical, common.TeamTerrorists)
+	watch("m_bCTTimeOutActive", "ct", constants.TimeoutTypeTactical, common.TeamCounterTerrorists)
+	watch("m_bTechnicalTimeOut", "technical", constants.TimeoutTypeTechnical, common.TeamUnassigned)
+	watch("m_bMatchWaitingForResume", "pause", constants.TimeoutTypePause, common.TeamUnassigned)
+}
+
+func parseChatCommand(message string) string {
+	message = strings.ToLower(strings.TrimSpace(message))
+	if len(message) < 2 || (message[0] != '!' && message[0] != '.') {
+		return ""
+	}
+
+	return strings.Fields(message[1:] + " ")[0]
+}
+
+// Tactical requests trigger tactical timeouts of the same side, technical and pause requests trigger technical
+// timeouts or pauses.
+func isTimeoutTriggeredByRequest(timeout *Timeout, requestType constants.TimeoutType, requesterSide common.Team) bool {
+	if requestType == constants.TimeoutTypeTactical {
+		return timeout.Type == constants.TimeoutTypeTactical && timeout.Side == requesterSide
+	}
+
+	return timeout.Type == constants.TimeoutTypeTechnical || timeout.Type == constants.TimeoutTypePause
+}
+
+// Attach chat requests (!tac, .tech, !pause...) to the next game rules timeout of the same or next round they may have
+// triggered.
+// When the game rules don't have the props of a timeout type, requests are exported as chat timeouts. Chat pauses and
+// technical timeouts end with the next unpause command.
+func attachTimeoutChatRequests(match *Match) {
+	gameRulesTimeouts := match.Timeouts
+	var chatTimeouts []*Timeout
+	var openChatPauses []*Timeout
+	// Timeouts without team in the game rules, their team is the one of the requester.
+	requestTeamTimeouts := make(map[*Timeout]bool)
+	for _, message := range match.ChatMessages {
+		command := parseChatCommand(message.Message)
+		if command == "" {
+			continue
+		}
+
+		if slices.Contains(unpauseChatCommands, command) {
+			for _, timeout := range openChatPauses {
+				timeout.EndFrame = message.Frame
+				timeout.EndTick = message.Tick
+			}
+			openChatPauses = nil
+			continue
+		}
+
+		timeoutType, isTimeoutCommand := timeoutChatCommands[command]
+		if !isTimeoutCommand {
+			continue
+		}
+
+		// A request triggers a timeout of the same round or of the next one, e.g. a tactical timeout requested during a
+		// round starts at the next freeze time. Older requests have been ignored by the server.
+		var triggeredTimeout *Timeout
+		for _, timeout := range gameRulesTimeouts {
+			isRequestRound := timeout.RoundNumber == message.RoundNumber || timeout.RoundNumber == message.RoundNumber+1
+			if timeout.Tick >= message.Tick && isRequestRound && isTimeoutTriggeredByRequest(timeout, timeoutType, message.SenderSide) {
+				triggeredTimeout = timeout
+				break
+			}
+		}
+
+		if triggeredTimeout != nil {
+			// Messages are sorted chronologically, the latest request is kept when several players asked for it.
+			triggeredTimeout.RequesterName = message.SenderName
+			triggeredTimeout.RequesterSteamID64 = message.SenderSteamID64
+			if timeoutType == triggeredTimeout.Type && (triggeredTimeout.TeamName == "" || requestTeamTimeouts[triggeredTimeout]) {
+				triggeredTimeout.Side = message.SenderSide
+				_, triggeredTimeout.TeamName = match.playerSideAtRound(message.SenderSteamID64, message.RoundNumber)
+				requestTeamTimeouts[triggeredTimeout] = true
+			}
+			continue
+		}
+
+		// The request has been refused or ignored by the server.
+		if match.gameRulesTimeoutTypes[timeoutType] {
+			continue
+		}
+
+		_, teamName := match.playerSideAtRound(message.SenderSteamID64, message.RoundNumber)
+		timeout := &Timeout{
+			Frame:              message.Frame,
+			Tick:               message.Tick,
+			RoundNumber:        message.RoundNumber,
+			Type:               timeoutType,
+			Source:             constants.TimeoutSourceChat,
+			Side:               message.SenderSide,
+			TeamName:           teamName,
+			RequesterName:      message.SenderName,
+			RequesterSteamID64: message.SenderSteamID64,
+		}
+		chatTimeouts = append(chatTimeouts, timeout)
+		if timeoutType != constants.TimeoutTypeTactical {
+			openChatPauses = append(openChatPauses, timeout)
+		}
+	}
+
+	match.Timeouts = append(match.Timeouts, chatTimeouts...)
+	sort.SliceStable(match.Timeouts, func(i int, j int) bool {
+		return match.Timeouts[i].Tick < match.Timeouts[j].Tick
+	})
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/akiver/cs-demo-analyzer/pkg/api/constants"
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
+)
+
+func TestAttachTimeoutChatRequests(t *testing.T) {
+	// Requested during round 2, the timeout starts at the freeze time of round 3.
+	tTimeout := &Timeout{Tick: 4000, EndTick: 6000, RoundNumber: 3, Type: constants.TimeoutTypeTactical, Source: constants.TimeoutSourceGameRules, TeamName: "Team A", Side: common.TeamTerrorists}
+	ctTimeout := &Timeout{Tick: 9000, EndTick: 11000, RoundNumber: 5, Type: constants.TimeoutTypeTactical, Source: constants.TimeoutSourceGameRules, TeamName: "Team B", Side: common.TeamCounterTerrorists}
+	match := &Match{
+		TickRate: 64,
+		Timeouts: []*Timeout{tTimeout, ctTimeout},
+		ChatMessages: []*ChatMessage{
+			{Frame: 500, Tick: 500, RoundNumber: 2, Message: "!tac", SenderName: "ct", SenderSteamID64: 2, SenderSide: common.TeamCounterTerrorists},
+			{Frame: 900, Tick: 900, RoundNumber: 2, Message: ".TAC", SenderName: "t", SenderSteamID64: 1, SenderSide: common.TeamTerrorists},
+			{Frame: 950, Tick: 950, RoundNumber: 2, Message: "!tac", SenderName: "t2", SenderSteamID64: 3, SenderSide: common.TeamTerrorists},
+			{Frame: 5000, Tick: 5000, RoundNumber: 4, Message: "!pause", SenderName: "t", SenderSteamID64: 1, SenderSide: common.TeamTerrorists},
+			{Frame: 5500, Tick: 5500, RoundNumber: 4, Message: ".r", SenderName: "ct", SenderSteamID64: 2, SenderSide: common.TeamCounterTerrorists},
+			{Frame: 6000, Tick: 6000, RoundNumber: 4, Message: "!unpause", SenderName: "ct", SenderSteamID64: 2, SenderSide: common.TeamCounterTerrorists},
+			{Frame: 7000, Tick: 7000, RoundNumber: 4, Message: "!unpause", SenderName: "ct", SenderSteamID64: 2, SenderSide: common.TeamCounterTerrorists},
+		},
+		gameRulesTimeoutTypes: map[constants.TimeoutType]bool{
+			constants.TimeoutTypeTactical:  true,
+			constants.TimeoutTypeTechnical: true,
+		},
+	}
+
+	attachTimeoutChatRequests(match)
+
+	if tTimeout.RequesterSteamID64 != 3 || tTimeout.RequesterName != "t2" {
+		t.Errorf("expected the latest T request to be attached to the T timeout, got requester %d", tTimeout.RequesterSteamID64)
+	}
+	// The CT request of the round 2 has been ignored, the CT timeout of the round 5 has been requested by someone else.
+	if ctTimeout.RequesterSteamID64 != 0 {
+		t.Errorf("expected the old CT request not to be attached to the CT timeout, got requester %d", ctTimeout.RequesterSteamID64)
+	}
+	// Tactical requests are not exported alone because the game rules have the tactical timeout props and the
+	// pause has been typed in the chat only.
+	if len(match.Timeouts) != 3 {
+		t.Fatalf("expected 3 timeouts, got %d", len(match.Timeouts))
+	}
+	pause := match.Timeouts[1]
+	if pause.Type != constants.TimeoutTypePause || pause.Source != constants.TimeoutSourceChat || pause.EndTick != 6000 {
+		t.Fatalf("expected a chat pause ending at the first unpause command, got %+v", pause)
+	}
+}
+
+func TestAttachTimeoutChatRequests_PreviousRoundRequest(t *testing.T) {
+	ctTimeout := &Timeout{Tick: 9000, EndTick: 11000, RoundNumber: 5, Type: constants.TimeoutTypeTactical, Source: constants.TimeoutSourceGameRules, TeamName: "Team B", Side: common.TeamCounterTerrorists}
+	technical := &Timeout{Tick: 12000, EndTick: 13000, RoundNumber: 5, Type: constants.TimeoutTypeTechnical, Source: constants.TimeoutSourceGameRules}
+	match := &Match{
+		TickRate: 64,
+		Timeouts: []*Timeout{ctTimeout, technical},
+		ChatMessages: []*ChatMessage{
+			{Frame: 8500, Tick: 8500, RoundNumber: 4, Message: "!tac", SenderName: "ct", SenderSteamID64: 2, SenderSide: common.TeamCounterTerrorists},
+			{Frame: 11500, Tick: 11500, RoundNumber: 5, Message: "!tech", SenderName: "t", SenderSteamID64: 1, SenderSide: common.TeamTerrorists},
+			{Frame: 11800, Tick: 11800, RoundNumber: 5, Message: "!tech", SenderName: "ct", SenderSteamID64: 2, SenderSide: common.TeamCounterTerrorists},
+		},
+		gameRulesTimeoutTypes: map[constants.TimeoutType]bool{
+			constants.TimeoutTypeTactical:  true,
+			constants.TimeoutTypeTechnical: true,
+		},
+	}
+
+	attachTimeoutChatRequests(match)
+
+	if ctTimeout.RequesterSteamID64 != 2 {
+		t.Errorf("expected the CT request of the previous round to be attached, got requester %d", ctTimeout.RequesterSteamID64)
+	}
+	if technical.RequesterSteamID64 != 2 || technical.Side != common.TeamCounterTerrorists {
+		t.Errorf("expected the latest technical request to be attached with its side, got %+v", technical)
+	}
+	if len(match.Timeouts) != 2 {
+		t.Errorf("expected no chat timeouts, got %d timeouts", len(match.Timeouts))
+	}
+}
+
+func TestAttachTimeoutChatRequests_WithoutGameRulesProps(t *testing.T) {
+	match := &Match{
+		TickRate: 64,
+		Timeouts: []*Timeout{},
+		ChatMessages: []*ChatMessage{
+			{Frame: 500, Tick: 500, RoundNumber: 2, Message: "!tac", SenderName: "ct", SenderSteamID64: 2, SenderSide: common.TeamCounterTerrorists},
+			{Frame: 900, Tick: 900, RoundNumber: 2, Message: "!tech", SenderName: "t", SenderSteamID64: 1, SenderSide: common.TeamTerrorists},
+			{Frame: 1000, Tick: 1000, RoundNumber: 2, Message: "!up", SenderName: "t", SenderSteamID64: 1, SenderSide: common.TeamTerrorists},
+		},
+		gameRulesTimeoutTypes: map[constants.TimeoutType]bool{},
+	}
+
+	attachTimeoutChatRequests(match)
+
+	if len(match.Timeouts) != 2 {
+		t.Fatalf("expected 2 chat timeouts, got %d", len(match.Timeouts))
+	}
+	tactical, technical := match.Timeouts[0], match.Timeouts[1]
+	if tactical.Type != constants.TimeoutTypeTactical || tactical.Source != constants.TimeoutSourceChat || tactical.EndTick != 0 {
+		t.Errorf("expected a chat tactical timeout not ended by the unpause, got %+v", tactical)
+	}
+	if technical.Type != constants.TimeoutTypeTechnical || technical.EndTick != 1000 {
+		t.Errorf("expected a chat technical timeout ended by the unpause, got %+v", technical)
+	}
+}
@@ -20,6 +20,12 @@ const (
 	UtilityTypeIncendiary UtilityType = "incendiary"
 )
 
+// Default 2D speed buckets of the thrower, see AnalyzerConfig.
+const (
+	throwerStepMaxSpeed = 80.0
+	throwerWalkMaxSpeed = 180.0
+)
+
 type UtilityThrowType string
 
 const (
@@ -111,7 +117,7 @@ func newUtilityFromShot(analyzer *Analyzer, shot *Shot, thrower *common.Player,
 		ThrowerVelocityY: shot.PlayerVelocityY,
 		ThrowerVelocityZ: shot.PlayerVelocityZ,
 		ThrowerSpeed2D:   getSpeed2D(shot.PlayerVelocityX, shot.PlayerVelocityY),
-		ThrowerSpeedType: classifyThrowerSpeedType(getSpeed2D(shot.PlayerVelocityX, shot.PlayerVelocityY)),
+		ThrowerSpeedType: classifyThrowerSpeedType(getSpeed2D(shot.PlayerVelocityX, shot.PlayerVelocityY), analyzer.config),
 		ThrowerPitch:     shot.Pitch,
 		ThrowerYaw:       shot.Yaw,
 	}
@@ -198,14 +204,14 @@ func calcThrowStrength(utility *Utility) float64 {
 	}
 	return baseSpeedXY / cosPitch
 }
-func classifyThrowerSpeedType(speed2D float64) string {
+func classifyThrowerSpeedType(speed2D float64, config AnalyzerConfig) string {
 	if speed2D == 0 {
 		return "standing"
 	}
-	if speed2D < 80 {
+	if speed2D < config.ThrowerStepMaxSpeed {
 		return "step"
 	}
-	if speed2D < 180 {
+	if speed2D < config.ThrowerWalkMaxSpeed {
 		return "walk"
 	}
 	return "run"
@@ -3,6 +3,7 @@ package api
 import (
 	"github.com/golang/geo/r3"
 	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
+	st "github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/sendtables"
 )
 
 // getPlayerVelocity calculates player velocity using position deltas between ticks.
@@ -105,3 +106,30 @@ func getPlayerPositionEyes(p *common.Player) r3.Vector {
 
 	return pos.Add(offset)
 }
+
+// PropertyValue getters panic when the underlying type doesn't match, numeric props may be decoded as different
+// types depending on the game version, this returns 0 for non-numeric values.
+func propertyValueToFloat64(value st.PropertyValue) float64 {
+	switch v := value.Any.(type) {
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint32:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	case bool:
+		if v {
+			return 1
+		}
+	}
+
+	return 0
+}
+
+func propertyValueToInt(value st.PropertyValue) int {
+	return int(propertyValueToFloat64(value))
+}
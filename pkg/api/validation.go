@@ -0,0 +1,239 @@
+package api
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/akiver/cs-demo-analyzer/pkg/api/constants"
+)
+
+// Maximum number of kills in a round without suicides when the number of connected players is unknown, 5 vs 5 players.
+const defaultMaxKillCountPerRound = 10
+
+// Checks related to the score weigh more in the confidence because a wrong score usually means missing rounds.
+var validationCheckWeights = map[constants.ValidationCheck]float64{
+	constants.ValidationCheckTeamScores:      3,
+	constants.ValidationCheckRoundNumbers:    3,
+	constants.ValidationCheckRoundTicks:      2,
+	constants.ValidationCheckRoundKills:      2,
+	constants.ValidationCheckPlayerEconomies: 1,
+	constants.ValidationCheckMaxRounds:       1,
+}
+
+type ValidationCheckResult struct {
+	Check   constants.ValidationCheck `json:"check"`
+	Passed  bool                      `json:"passed"`
+	Details string                    `json:"details"` // Why the check failed, empty if it passed
+}
+
+// Consistency checks done once the analysis is done.
+type ValidationReport struct {
+	Checks     []*ValidationCheckResult `json:"checks"`
+	Confidence float64                  `json:"confidence"` // Weighted ratio of passed checks, from 0 to 1
+}
+
+func (report *ValidationReport) Passed() bool {
+	return len(report.FailedChecks()) == 0
+}
+
+func (report *ValidationReport) FailedChecks() []constants.ValidationCheck {
+	checks := []constants.ValidationCheck{}
+	for _, result := range report.Checks {
+		if !result.Passed {
+			checks = append(checks, result.Check)
+		}
+	}
+
+	return checks
+}
+
+// Returns an error listing the failed checks, nil if all checks passed.
+func (report *ValidationReport) err() error {
+	failedChecks := []string{}
+	for _, check := range report.FailedChecks() {
+		failedChecks = append(failedChecks, check.String())
+	}
+	if len(failedChecks) == 0 {
+		return nil
+	}
+
+	return newError(ErrorCodeValidationFailed, "validation failed: "+strings.Join(failedChecks, ","), nil)
+}
+
+func (report *ValidationReport) addCheck(check constants.ValidationCheck, failures []string) {
+	report.Checks = append(report.Checks, &ValidationCheckResult{
+		Check:   check,
+		Passed:  len(failures) == 0,
+		Details: strings.Join(failures, "; "),
+	})
+}
+
+func validateTeamScores(match *Match) []string {
+	var failures []string
+	teamARoundsWon := 0
+	teamBRoundsWon := 0
+	for _, round := range match.Rounds {
+		if round.WinnerSide == round.TeamASide {
+			teamARoundsWon++
+		} else if round.WinnerSide == round.TeamBSide {
+			teamBRoundsWon++
+		}
+	}
+
+	if match.TeamA.Score != teamARoundsWon {
+		failures = append(failures, fmt.Sprintf("team A score is %d but it won %d rounds", match.TeamA.Score, teamARoundsWon))
+	}
+	if match.TeamB.Score != teamBRoundsWon {
+		failures = append(failures, fmt.Sprintf("team B score is %d but it won %d rounds", match.TeamB.Score, teamBRoundsWon))
+	}
+
+	if len(match.Rounds) > 0 {
+		lastRound := match.Rounds[len(match.Rounds)-1]
+		if lastRound.TeamAScore != match.TeamA.Score || lastRound.TeamBScore != match.TeamB.Score {
+			failures = append(failures, fmt.Sprintf(
+				"last round score is %d-%d but teams score is %d-%d",
+				lastRound.TeamAScore,
+				lastRound.TeamBScore,
+				match.TeamA.Score,
+				match.TeamB.Score,
+			))
+		}
+	}
+
+	return failures
+}
+
+func validateRoundNumbers(match *Match) []string {
+	var failures []string
+	if len(match.Rounds) == 0 {
+		return []string{"no rounds"}
+	}
+
+	for index, round := range match.Rounds {
+		if round.Number != index+1 {
+			failures = append(failures, fmt.Sprintf("round %d is at position %d", round.Number, index+1))
+		}
+	}
+
+	return failures
+}
+
+func validateRoundTicks(match *Match) []string {
+	var failures []string
+	for _, round := range match.Rounds {
+		if round.StartTick >= round.FreezeTimeEndTick || round.FreezeTimeEndTick >= round.EndTick {
+			failures = append(failures, fmt.Sprintf(
+				"round %d start %d, freeze time end %d, end %d",
+				round.Number,
+				round.StartTick,
+				round.FreezeTimeEndTick,
+				round.EndTick,
+			))
+		}
+	}
+
+	return failures
+}
+
+func validateRoundKills(match *Match) []string {
+	killCountByRound := make(map[int]int)
+	suicideCountByRound := make(map[int]int)
+	for _, kill := range match.Kills {
+		killCountByRound[kill.RoundNumber]++
+		if kill.IsSuicide() {
+			suicideCountByRound[kill.RoundNumber]++
+		}
+	}
+
+	var failures []string
+	for _, round := range match.Rounds {
+		// A player can't die more than once per round.
+		maxKillCount := match.connectedPlayerCountByRound[round.Number]
+		if maxKillCount == 0 {
+			maxKillCount = defaultMaxKillCountPerRound
+		}
+		killCount := killCountByRound[round.Number]
+		if killCount > maxKillCount+suicideCountByRound[round.Number] {
+			failures = append(failures, fmt.Sprintf("round %d has %d kills", round.Number, killCount))
+		}
+	}
+
+	return failures
+}
+
+func validatePlayerEconomies(match *Match) []string {
+	hasEconomy := make(map[roundPlayerKey]bool)
+	for _, economy := range match.PlayerEconomies {
+		hasEconomy[roundPlayerKey{roundNumber: economy.RoundNumber, steamID64: economy.SteamID64}] = true
+	}
+
+	var failures []string
+	for key := range match.playerRoundsPlayed {
+		if !hasEconomy[key] {
+			failures = append(failures, fmt.Sprintf("player %d has no economy in round %d", key.steamID64, key.roundNumber))
+		}
+	}
+	// Map iteration order is random.
+	slices.Sort(failures)
+
+	return failures
+}
+
+func validateMaxRounds(match *Match) []string {
+	if match.MaxRounds <= 0 {
+		return []string{"max rounds not detected"}
+	}
+
+	var failures []string
+	regulationRoundCount := 0
+	hasOvertime := false
+	for _, round := range match.Rounds {
+		if round.OvertimeNumber == 0 {
+			regulationRoundCount++
+		} else {
+			hasOvertime = true
+		}
+	}
+
+	if regulationRoundCount > match.MaxRounds {
+		failures = append(failures, fmt.Sprintf("%d regulation rounds for %d max rounds", regulationRoundCount, match.MaxRounds))
+	}
+	if hasOvertime && regulationRoundCount != match.MaxRounds {
+		failures = append(failures, fmt.Sprintf("overtime after %d regulation rounds for %d max rounds", regulationRoundCount, match.MaxRounds))
+	}
+	if !hasOvertime {
+		winningScore := match.MaxRounds/2 + 1
+		if match.TeamA.Score > winningScore || match.TeamB.Score > winningScore {
+			failures = append(failures, fmt.Sprintf("score %d-%d is higher than %d without overtime", match.TeamA.Score, match.TeamB.Score, winningScore))
+		}
+	}
+
+	return failures
+}
+
+// Must be called once the rounds score have been computed.
+func newValidationReport(match *Match) *ValidationReport {
+	report := &ValidationReport{
+		Checks: []*ValidationCheckResult{},
+	}
+	report.addCheck(constants.ValidationCheckTeamScores, validateTeamScores(match))
+	report.addCheck(constants.ValidationCheckRoundNumbers, validateRoundNumbers(match))
+	report.addCheck(constants.ValidationCheckRoundTicks, validateRoundTicks(match))
+	report.addCheck(constants.ValidationCheckRoundKills, validateRoundKills(match))
+	report.addCheck(constants.ValidationCheckPlayerEconomies, validatePlayerEconomies(match))
+	report.addCheck(constants.ValidationCheckMaxRounds, validateMaxRounds(match))
+
+	var totalWeight float64
+	var passedWeight float64
+	for _, result := range report.Checks {
+		weight := validationCheckWeights[result.Check]
+		totalWeight += weight
+		if result.Passed {
+			passedWeight += weight
+		}
+	}
+	report.Confidence = passedWeight / totalWeight
+
+	return report
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/akiver/cs-demo-analyzer/pkg/api/constants"
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
+)
+
+func newValidationTestMatch() *Match {
+	ct := common.TeamCounterTerrorists
+	t := common.TeamTerrorists
+	match := &Match{
+		MaxRounds: 4,
+		TeamA:     &Team{Score: 3},
+		TeamB:     &Team{Score: 0},
+		Kills:     []*Kill{},
+		PlayerEconomies: []*PlayerEconomy{
+			{RoundNumber: 1, SteamID64: 1},
+			{RoundNumber: 2, SteamID64: 1},
+			{RoundNumber: 3, SteamID64: 1},
+		},
+		playerRoundsPlayed: map[roundPlayerKey]bool{
+			{roundNumber: 1, steamID64: 1}: true,
+			{roundNumber: 2, steamID64: 1}: true,
+			{roundNumber: 3, steamID64: 1}: true,
+		},
+	}
+	for number := 1; number <= 3; number++ {
+		match.Rounds = append(match.Rounds, &Round{
+			Number:            number,
+			StartTick:         number * 100,
+			FreezeTimeEndTick: number*100 + 10,
+			EndTick:           number*100 + 50,
+			TeamASide:         ct,
+			TeamBSide:         t,
+			WinnerSide:        ct,
+			TeamAScore:        number,
+		})
+	}
+
+	return match
+}
+
+func TestValidationReport_Passes(t *testing.T) {
+	report := newValidationReport(newValidationTestMatch())
+
+	if !report.Passed() {
+		t.Fatalf("expected all checks to pass, failed checks: %v", report.FailedChecks())
+	}
+	if report.Confidence != 1 {
+		t.Errorf("expected confidence 1 got %f", report.Confidence)
+	}
+	if report.err() != nil {
+		t.Errorf("expected no error got %v", report.err())
+	}
+}
+
+func TestValidationReport_Fails(t *testing.T) {
+	match := newValidationTestMatch()
+	match.Rounds[2].Number = 4
+	match.Rounds[1].FreezeTimeEndTick = -1
+	// 11 kills are allowed in the 1st round thanks to the suicide.
+	for index := 0; index < 10; index++ {
+		match.Kills = append(match.Kills, &Kill{RoundNumber: 1, KillerSteamID64: 1, VictimSteamID64: uint64(index + 2)})
+	}
+	match.Kills = append(match.Kills, &Kill{RoundNumber: 1, KillerSteamID64: 20, VictimSteamID64: 20})
+	match.playerRoundsPlayed[roundPlayerKey{roundNumber: 2, steamID64: 2}] = true
+
+	report := newValidationReport(match)
+
+	expected := []constants.ValidationCheck{
+		constants.ValidationCheckRoundNumbers,
+		constants.ValidationCheckRoundTicks,
+		constants.ValidationCheckPlayerEconomies,
+	}
+	if !slices.Equal(report.FailedChecks(), expected) {
+		t.Fatalf("expected failed checks %v got %v", expected, report.FailedChecks())
+	}
+	if report.Confidence != 6.0/12.0 {
+		t.Errorf("expected confidence 0.5 got %f", report.Confidence)
+	}
+	if !errors.Is(report.err(), ErrValidationFailed) {
+		t.Errorf("expected ErrValidationFailed got %v", report.err())
+	}
+}
+
+func TestValidateMaxRounds(t *testing.T) {
+	match := newValidationTestMatch()
+	match.MaxRounds = 2
+	if failures := validateMaxRounds(match); len(failures) != 2 {
+		t.Errorf("expected 2 failures with 3 regulation rounds for 2 max rounds, got %v", failures)
+	}
+
+	match = newValidationTestMatch()
+	match.Rounds[2].OvertimeNumber = 1
+	if failures := validateMaxRounds(match); len(failures) != 1 {
+		t.Errorf("expected overtime to fail before max rounds, got %v", failures)
+	}
+}
+
+func TestValidateRoundKills_UsesConnectedPlayerCount(t *testing.T) {
+	match := newValidationTestMatch()
+	// 2 vs 2 in the 1st round, the connected players count is unknown in the 2nd round.
+	match.connectedPlayerCountByRound = map[int]int{1: 4}
+	for index := 0; index < 5; index++ {
+		match.Kills = append(match.Kills, &Kill{RoundNumber: 1, KillerSteamID64: 1, VictimSteamID64: uint64(index + 2)})
+		match.Kills = append(match.Kills, &Kill{RoundNumber: 2, KillerSteamID64: 1, VictimSteamID64: uint64(index + 2)})
+	}
+
+	failures := validateRoundKills(match)
+	if len(failures) != 1 || failures[0] != "round 1 has 5 kills" {
+		t.Errorf("expected only the round 1 to have too many kills, got %v", failures)
+	}
+}
@@ -0,0 +1,202 @@
+package api
+
+import (
+	"net"
+	"time"
+
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/msg"
+)
+
+const steamID64AccountIDOffset = 76561197960265728
+
+type ValveMatchInfoPlayer struct {
+	AccountID     uint32 `json:"accountId"`
+	SteamID64     uint64 `json:"steamId"`
+	Name          string `json:"name"` // Empty if the player has not been found in the demo
+	KillCount     int    `json:"killCount"`
+	AssistCount   int    `json:"assistCount"`
+	DeathCount    int    `json:"deathCount"`
+	Score         int    `json:"score"`
+	MvpCount      int    `json:"mvpCount"`
+	HeadshotCount int    `json:"headshotCount"`
+	RankID        int    `json:"rankId"`
+	RankTypeID    int    `json:"rankTypeId"`
+	WinCount      int    `json:"winCount"` // Competitive wins count of the rank type
+}
+
+type ValveMatchInfoRound struct {
+	Number          int `json:"number"`
+	FirstTeamScore  int `json:"firstTeamScore"` // Teams are in the game coordinator order which may differ from the TeamA / TeamB order
+	SecondTeamScore int `json:"secondTeamScore"`
+	RoundResult     int `json:"roundResult"`
+	MatchResult     int `json:"matchResult"`
+	DurationSeconds int `json:"durationSeconds"` // Match duration when the round ended
+}
+
+// Data coming from the .info file written by Valve next to matchmaking demos.
+type ValveMatchInfo struct {
+	MatchID                   uint64                  `json:"matchId"`
+	ReservationID             uint64                  `json:"reservationId"`
+	ServerIP                  string                  `json:"serverIp"`
+	TvPort                    uint32                  `json:"tvPort"`
+	MatchTime                 time.Time               `json:"matchTime"` // When the match became watchable
+	Players                   []*ValveMatchInfoPlayer `json:"players"`
+	Rounds                    []*ValveMatchInfoRound  `json:"rounds"`
+	ScoreMismatchRoundNumbers []int                   `json:"scoreMismatchRoundNumbers"` // Rounds where the analyzed score differs from the .info file
+}
+
+func serverIPToString(ip uint32) string {
+	if ip == 0 {
+		return ""
+	}
+
+	return net.IPv4(byte(ip>>24), byte(ip>>16), byte(ip>>8), byte(ip)).String()
+}
+
+func valueAtIndex(values []int32, index int) int {
+	if index < len(values) {
+		return int(values[index])
+	}
+
+	return 0
+}
+
+func newValveMatchInfo(matchInfo *msg.CDataGCCStrike15V2_MatchInfo) *ValveMatchInfo {
+	if matchInfo == nil {
+		return nil
+	}
+
+	watchableMatchInfo := matchInfo.GetWatchablematchinfo()
+	info := &ValveMatchInfo{
+		MatchID:                   matchInfo.GetMatchid(),
+		ServerIP:                  serverIPToString(watchableMatchInfo.GetServerIp()),
+		TvPort:                    watchableMatchInfo.GetTvPort(),
+		MatchTime:                 time.Unix(int64(matchInfo.GetMatchtime()), 0),
+		Players:                   []*ValveMatchInfoPlayer{},
+		Rounds:                    []*ValveMatchInfoRound{},
+		ScoreMismatchRoundNumbers: []int{},
+	}
+
+	roundStats := matchInfo.GetRoundstatsall()
+	if len(roundStats) == 0 && matchInfo.GetRoundstatsLegacy() != nil {
+		roundStats = []*msg.CMsgGCCStrike15V2_MatchmakingServerRoundStats{matchInfo.GetRoundstatsLegacy()}
+	}
+	if len(roundStats) == 0 {
+		return info
+	}
+
+	// Only the 1st stats usually contain the reservation with the players account IDs and ranks.
+	var reservation *msg.CMsgGCCStrike15V2_MatchmakingGC2ServerReserve
+	for _, stats := range roundStats {
+		if stats.GetReservation() != nil {
+			reservation = stats.GetReservation()
+			break
+		}
+	}
+
+	roundIndexByNumber := make(map[int]int)
+	for _, stats := range roundStats {
+		teamScores := stats.GetTeamScores()
+		firstTeamScore := valueAtIndex(teamScores, 0)
+		secondTeamScore := valueAtIndex(teamScores, 1)
+		// The round number is not reliable, the sum of the scores is the number of rounds played.
+		number := firstTeamScore + secondTeamScore
+		if number == 0 {
+			continue
+		}
+
+		round := &ValveMatchInfoRound{
+			Number:          number,
+			FirstTeamScore:  firstTeamScore,
+			SecondTeamScore: secondTeamScore,
+			RoundResult:     int(stats.GetRoundResult()),
+			MatchResult:     int(stats.GetMatchResult()),
+			DurationSeconds: int(stats.GetMatchDuration()),
+		}
+		if index, exists := roundIndexByNumber[number]; exists {
+			info.Rounds[index] = round
+		} else {
+			roundIndexByNumber[number] = len(info.Rounds)
+			info.Rounds = append(info.Rounds, round)
+		}
+	}
+
+	// Players stats are cumulative, the last stats contain the final values.
+	lastStats := roundStats[len(roundStats)-1]
+	info.ReservationID = lastStats.GetReservationid()
+	if reservation == nil {
+		return info
+	}
+
+	for index, accountID := range reservation.GetAccountIds() {
+		player := &ValveMatchInfoPlayer{
+			AccountID:     accountID,
+			SteamID64:     uint64(accountID) + steamID64AccountIDOffset,
+			KillCount:     valueAtIndex(lastStats.GetKills(), index),
+			AssistCount:   valueAtIndex(lastStats.GetAssists(), index),
+			DeathCount:    valueAtIndex(lastStats.GetDeaths(), index),
+			Score:         valueAtIndex(lastStats.GetScores(), index),
+			MvpCount:      valueAtIndex(lastStats.GetMvps(), index),
+			HeadshotCount: valueAtIndex(lastStats.GetEnemyHeadshots(), index),
+		}
+		for _, ranking := range reservation.GetRankings() {
+			if ranking.GetAccountId() == accountID {
+				player.RankID = int(ranking.GetRankId())
+				player.RankTypeID = int(ranking.GetRankTypeId())
+				player.WinCount = int(ranking.GetWins())
+				break
+			}
+		}
+		info.Players = append(info.Players, player)
+	}
+
+	return info
+}
+
+// Must be called once the rounds score have been computed.
+// Teams order is not known from the .info file, it's deduced from the first round where the scores of the teams
+// differ and then used to compare the scores of every round.
+func (info *ValveMatchInfo) crossCheck(match *Match) {
+	for _, player := range info.Players {
+		if matchPlayer, exists := match.PlayersBySteamID[player.SteamID64]; exists {
+			player.Name = matchPlayer.Name
+		}
+	}
+
+	roundsByNumber := make(map[int]*Round)
+	for _, round := range match.Rounds {
+		roundsByNumber[round.Number] = round
+	}
+
+	isOrderSwapped := false
+	for _, infoRound := range info.Rounds {
+		round, exists := roundsByNumber[infoRound.Number]
+		if !exists || infoRound.FirstTeamScore == infoRound.SecondTeamScore {
+			continue
+		}
+
+		if round.TeamAScore == infoRound.FirstTeamScore && round.TeamBScore == infoRound.SecondTeamScore {
+			break
+		}
+		if round.TeamAScore == infoRound.SecondTeamScore && round.TeamBScore == infoRound.FirstTeamScore {
+			isOrderSwapped = true
+			break
+		}
+	}
+
+	info.ScoreMismatchRoundNumbers = []int{}
+	for _, infoRound := range info.Rounds {
+		round, exists := roundsByNumber[infoRound.Number]
+		if !exists {
+			continue
+		}
+
+		teamAScore, teamBScore := infoRound.FirstTeamScore, infoRound.SecondTeamScore
+		if isOrderSwapped {
+			teamAScore, teamBScore = teamBScore, teamAScore
+		}
+		if round.TeamAScore != teamAScore || round.TeamBScore != teamBScore {
+			info.ScoreMismatchRoundNumbers = append(info.ScoreMismatchRoundNumbers, infoRound.Number)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/msg"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNewValveMatchInfo(t *testing.T) {
+	matchInfo := &msg.CDataGCCStrike15V2_MatchInfo{
+		Matchid:   proto.Uint64(3400360672356205056),
+		Matchtime: proto.Uint32(1700000000),
+		Watchablematchinfo: &msg.WatchableMatchInfo{
+			ServerIp: proto.Uint32(0x0A000102),
+			TvPort:   proto.Uint32(9725),
+		},
+		Roundstatsall: []*msg.CMsgGCCStrike15V2_MatchmakingServerRoundStats{
+			{
+				Reservation: &msg.CMsgGCCStrike15V2_MatchmakingGC2ServerReserve{
+					AccountIds: []uint32{1, 2},
+					Rankings:   []*msg.PlayerRankingInfo{{AccountId: proto.Uint32(2), RankId: proto.Uint32(15), RankTypeId: proto.Uint32(11), Wins: proto.Uint32(42)}},
+				},
+			},
+			{TeamScores: []int32{1, 0}, Reservationid: proto.Uint64(1)},
+			{TeamScores: []int32{1, 1}, Reservationid: proto.Uint64(2)},
+			{TeamScores: []int32{1, 1}, Reservationid: proto.Uint64(3), Kills: []int32{3, 5}, Mvps: []int32{1}},
+		},
+	}
+
+	info := newValveMatchInfo(matchInfo)
+	if info.ServerIP != "10.0.1.2" || info.TvPort != 9725 || info.ReservationID != 3 {
+		t.Fatalf("unexpected match info %+v", info)
+	}
+	if len(info.Rounds) != 2 || info.Rounds[1].Number != 2 {
+		t.Fatalf("expected 2 rounds, got %d", len(info.Rounds))
+	}
+	if len(info.Players) != 2 {
+		t.Fatalf("expected 2 players, got %d", len(info.Players))
+	}
+	player := info.Players[1]
+	if player.SteamID64 != 76561197960265730 || player.KillCount != 5 || player.MvpCount != 0 || player.RankID != 15 || player.WinCount != 42 {
+		t.Fatalf("unexpected player %+v", player)
+	}
+
+	match := &Match{
+		PlayersBySteamID: map[uint64]*Player{76561197960265730: {Name: "foo"}},
+		Rounds: []*Round{
+			{Number: 1, TeamAScore: 0, TeamBScore: 1},
+			{Number: 2, TeamAScore: 2, TeamBScore: 0},
+			// Matches the .info scores only with the teams order of the round 1 swapped.
+			{Number: 3, TeamAScore: 2, TeamBScore: 1},
+		},
+	}
+	info.Rounds = append(info.Rounds, &ValveMatchInfoRound{Number: 3, FirstTeamScore: 2, SecondTeamScore: 1})
+	info.crossCheck(match)
+	if player.Name != "foo" {
+		t.Fatalf("expected player name to be set from the match players")
+	}
+	if len(info.ScoreMismatchRoundNumbers) != 2 || info.ScoreMismatchRoundNumbers[0] != 2 || info.ScoreMismatchRoundNumbers[1] != 3 {
+		t.Fatalf("expected rounds 2 and 3 score mismatch, got %v", info.ScoreMismatchRoundNumbers)
+	}
+}
@@ -0,0 +1,273 @@
+package api
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/akiver/cs-demo-analyzer/internal/ogg"
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/msg"
+)
+
+// Max silence between 2 voice packets of a player to consider that they are still speaking.
+const voiceActivityMaxGapSeconds = 0.5
+
+type VoiceActivity struct {
+	StartFrame      int         `json:"startFrame"`
+	StartTick       int         `json:"startTick"`
+	EndFrame        int         `json:"endFrame"`
+	EndTick         int         `json:"endTick"`
+	RoundNumber     int         `json:"roundNumber"`
+	PlayerName      string      `json:"playerName"`
+	PlayerSteamID64 uint64      `json:"playerSteamId"`
+	PlayerSide      common.Team `json:"playerSide"`
+	IsPlayerAlive   bool        `json:"isPlayerAlive"` // When the player started speaking
+	PacketCount     int         `json:"packetCount"`
+}
+
+func (activity *VoiceActivity) DurationSeconds(tickRate float64) float64 {
+	if tickRate <= 0 {
+		return 0
+	}
+
+	return float64(activity.EndTick-activity.StartTick) / tickRate
+}
+
+func newVoiceActivity(analyzer *Analyzer, player *common.Player, frame int, tick int) *VoiceActivity {
+	return &VoiceActivity{
+		StartFrame:      frame,
+		StartTick:       tick,
+		EndFrame:        frame,
+		EndTick:         tick,
+		RoundNumber:     analyzer.currentRound.Number,
+		PlayerName:      player.Name,
+		PlayerSteamID64: player.SteamID64,
+		PlayerSide:      player.Team,
+		IsPlayerAlive:   player.IsAlive(),
+	}
+}
+
+func (analyzer *Analyzer) playerFromVoiceData(message *msg.CSVCMsg_VoiceData) *common.Player {
+	participants := analyzer.parser.GameState().Participants()
+	if steamID64 := message.GetXuid(); steamID64 != 0 {
+		for _, player := range participants.All() {
+			if player.SteamID64 == steamID64 {
+				return player
+			}
+		}
+	}
+
+	if message.GetClient() < 0 {
+		return nil
+	}
+
+	return participants.ByEntityID()[int(message.GetClient())+1]
+}
+
+// Opus voice data may contain several packets, packet offsets are the end position of each packet.
+func splitOpusPackets(audio *msg.CMsgVoiceAudio) [][]byte {
+	data := audio.GetVoiceData()
+	offsets := audio.GetPacketOffsets()
+	if len(offsets) < 2 || int(offsets[len(offsets)-1]) != len(data) {
+		return [][]byte{data}
+	}
+
+	packets := make([][]byte, 0, len(offsets))
+	start := 0
+	for _, offset := range offsets {
+		end := int(offset)
+		if end <= start {
+			continue
+		}
+		packets = append(packets, data[start:end])
+		start = end
+	}
+
+	return packets
+}
+
+func (analyzer *Analyzer) registerVoiceData(message *msg.CSVCMsg_VoiceData) {
+	audio := message.GetAudio()
+	if audio == nil || len(audio.GetVoiceData()) == 0 {
+		return
+	}
+
+	player := analyzer.playerFromVoiceData(message)
+	if player == nil || player.SteamID64 == 0 {
+		return
+	}
+
+	activity := analyzer.updateVoiceActivity(player, analyzer.parser.CurrentFrame(), analyzer.currentTick())
+	if audio.GetFormat() != msg.VoiceDataFormatT_VOICEDATA_FORMAT_OPUS {
+		return
+	}
+	key := roundPlayerKey{roundNumber: activity.RoundNumber, steamID64: player.SteamID64}
+	if err := analyzer.match.voiceWriters.write(key, splitOpusPackets(audio)); err != nil {
+		panic(abortAnalysis{newError(ErrorCodeInternal, "failed to write voice file", err)})
+	}
+}
+
+// Extends the last voice activity of the player or creates a new one if they were silent for too long.
+func (analyzer *Analyzer) updateVoiceActivity(player *common.Player, frame int, tick int) *VoiceActivity {
+	activity := analyzer.voiceActivityBySteamID[player.SteamID64]
+	maxGapTicks := voiceActivityMaxGapSeconds * analyzer.match.TickRate
+	isNewActivity := activity == nil || activity.RoundNumber != analyzer.currentRound.Number ||
+		tick < activity.EndTick || float64(tick-activity.EndTick) >= maxGapTicks
+	if isNewActivity {
+		activity = newVoiceActivity(analyzer, player, frame, tick)
+		analyzer.match.VoiceActivities = append(analyzer.match.VoiceActivities, activity)
+		analyzer.voiceActivityBySteamID[player.SteamID64] = activity
+	}
+	activity.EndFrame = frame
+	activity.EndTick = tick
+	activity.PacketCount++
+
+	return activity
+}
+
+type voiceOggFile struct {
+	file   *os.File
+	writer *ogg.OpusWriter
+}
+
+// Writes the Opus packets of each player into an Ogg Opus file per round while the demo is parsed, named
+// <steamid>_round<number>.ogg. Only the files of the current round are open, packets are not kept in memory.
+// A nil writers is valid and writes nothing.
+type voiceOggWriters struct {
+	folder string
+	serial uint32
+	// A nil file means that the file has been closed.
+	files map[roundPlayerKey]*voiceOggFile
+}
+
+func newVoiceOggWriters(folder string) *voiceOggWriters {
+	if folder == "" {
+		return nil
+	}
+
+	return &voiceOggWriters{
+		folder: folder,
+		files:  make(map[roundPlayerKey]*voiceOggFile),
+	}
+}
+
+func (writers *voiceOggWriters) filePath(key roundPlayerKey) string {
+	return filepath.Join(writers.folder, fmt.Sprintf("%d_round%d.ogg", key.steamID64, key.roundNumber))
+}
+
+func (writers *voiceOggWriters) write(key roundPlayerKey, packets [][]byte) error {
+	if writers == nil {
+		return nil
+	}
+
+	file, exists := writers.files[key]
+	if !exists {
+		// Voice packets of previous rounds will not come anymore.
+		for otherKey := range writers.files {
+			if otherKey.roundNumber != key.roundNumber {
+				if err := writers.closeFile(otherKey); err != nil {
+					return err
+				}
+			}
+		}
+
+		var err error
+		file, err = writers.createFile(key)
+		if err != nil {
+			return err
+		}
+		writers.files[key] = file
+	}
+	if file == nil {
+		return nil
+	}
+
+	for _, packet := range packets {
+		if err := file.writer.WritePacket(packet); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (writers *voiceOggWriters) createFile(key roundPlayerKey) (*voiceOggFile, error) {
+	if err := os.MkdirAll(writers.folder, os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	file, err := os.Create(writers.filePath(key))
+	if err != nil {
+		return nil, err
+	}
+
+	writers.serial++
+	writer, err := ogg.NewOpusWriter(file, writers.serial, 48000, 1)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &voiceOggFile{file: file, writer: writer}, nil
+}
+
+func (writers *voiceOggWriters) closeFile(key roundPlayerKey) error {
+	file := writers.files[key]
+	if file == nil {
+		return nil
+	}
+	writers.files[key] = nil
+
+	err := file.writer.Close()
+	if closeErr := file.file.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// Deletes the files of the round, e.g. when the round is restored from a backup.
+func (writers *voiceOggWriters) discardRound(roundNumber int) {
+	if writers == nil {
+		return
+	}
+
+	for key := range writers.files {
+		if key.roundNumber == roundNumber {
+			writers.discard(key)
+		}
+	}
+}
+
+func (writers *voiceOggWriters) discardAll() {
+	if writers == nil {
+		return
+	}
+
+	for key := range writers.files {
+		writers.discard(key)
+	}
+}
+
+func (writers *voiceOggWriters) discard(key roundPlayerKey) {
+	_ = writers.closeFile(key)
+	_ = os.Remove(writers.filePath(key))
+	delete(writers.files, key)
+}
+
+// Closes the open files, it's safe to call it several times.
+func (writers *voiceOggWriters) close() error {
+	if writers == nil {
+		return nil
+	}
+
+	var err error
+	for key := range writers.files {
+		if closeErr := writers.closeFile(key); err == nil {
+			err = closeErr
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
+)
+
+func newVoiceActivityTestAnalyzer() *Analyzer {
+	return &Analyzer{
+		match: &Match{
+			TickRate:        64,
+			VoiceActivities: []*VoiceActivity{},
+		},
+		currentRound:           &Round{Number: 1},
+		voiceActivityBySteamID: make(map[uint64]*VoiceActivity),
+	}
+}
+
+func TestUpdateVoiceActivity_Timeline(t *testing.T) {
+	analyzer := newVoiceActivityTestAnalyzer()
+	foo := &common.Player{Name: "foo", SteamID64: 1, Team: common.TeamTerrorists}
+	bar := &common.Player{Name: "bar", SteamID64: 2, Team: common.TeamCounterTerrorists}
+
+	analyzer.updateVoiceActivity(foo, 100, 100)
+	analyzer.updateVoiceActivity(foo, 110, 110)
+	// Both players speak at the same time.
+	analyzer.updateVoiceActivity(bar, 115, 115)
+	// Less than 0.5 second of silence, foo is still speaking.
+	analyzer.updateVoiceActivity(foo, 140, 140)
+	// 0.5 second of silence, it's a new activity.
+	analyzer.updateVoiceActivity(foo, 172, 172)
+	analyzer.currentRound = &Round{Number: 2}
+	analyzer.updateVoiceActivity(foo, 180, 180)
+
+	activities := analyzer.match.VoiceActivities
+	if len(activities) != 4 {
+		t.Fatalf("expected 4 voice activities got %d", len(activities))
+	}
+
+	first := activities[0]
+	if first.PlayerSteamID64 != 1 || first.StartTick != 100 || first.EndTick != 140 || first.PacketCount != 3 {
+		t.Errorf("expected foo to speak from tick 100 to 140 with 3 packets got %+v", first)
+	}
+	if first.DurationSeconds(64) != 0.625 {
+		t.Errorf("expected a duration of 0.625 seconds got %f", first.DurationSeconds(64))
+	}
+	if activities[1].PlayerSteamID64 != 2 || activities[1].PlayerSide != common.TeamCounterTerrorists || activities[1].PacketCount != 1 {
+		t.Errorf("unexpected activity for bar %+v", activities[1])
+	}
+	if activities[2].StartTick != 172 || activities[2].RoundNumber != 1 {
+		t.Errorf("expected a new activity after the silence got %+v", activities[2])
+	}
+	// A new round starts a new activity even without silence.
+	if activities[3].StartTick != 180 || activities[3].RoundNumber != 2 {
+		t.Errorf("expected a new activity in round 2 got %+v", activities[3])
+	}
+}
+
+func TestVoiceOggWriters_StreamsPerRound(t *testing.T) {
+	folder := filepath.Join(t.TempDir(), "voice")
+	writers := newVoiceOggWriters(folder)
+	round1 := roundPlayerKey{roundNumber: 1, steamID64: 1}
+	round2 := roundPlayerKey{roundNumber: 2, steamID64: 1}
+	packet := []byte{0xfc, 0xff, 0xfe}
+
+	if err := writers.write(round1, [][]byte{packet, packet}); err != nil {
+		t.Fatal(err)
+	}
+	if err := writers.write(round2, [][]byte{packet}); err != nil {
+		t.Fatal(err)
+	}
+	if writers.files[round1] != nil {
+		t.Errorf("expected the file of the previous round to be closed")
+	}
+
+	// The round 2 has been restored from a backup.
+	writers.discardRound(2)
+	if err := writers.close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(writers.filePath(round1)); err != nil {
+		t.Errorf("expected the round 1 file to exist: %v", err)
+	}
+	if _, err := os.Stat(writers.filePath(round2)); !os.IsNotExist(err) {
+		t.Errorf("expected the round 2 file to be deleted got %v", err)
+	}
+
+	// Voice is not exported without folder.
+	disabled := newVoiceOggWriters("")
+	if err := disabled.write(round1, [][]byte{packet}); err != nil || disabled.close() != nil {
+		t.Errorf("expected nil writers to do nothing")
+	}
+}
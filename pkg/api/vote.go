@@ -0,0 +1,144 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/akiver/cs-demo-analyzer/pkg/api/constants"
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/events"
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/msg"
+	st "github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/sendtables"
+)
+
+type Vote struct {
+	Frame              int                   `json:"frame"`
+	Tick               int                   `json:"tick"`
+	EndFrame           int                   `json:"endFrame"`
+	EndTick            int                   `json:"endTick"`
+	RoundNumber        int                   `json:"roundNumber"`
+	Type               constants.VoteType    `json:"type"`
+	Side               common.Team           `json:"side"` // Team allowed to vote, unassigned when everyone can vote
+	CallerName         string                `json:"callerName"`
+	CallerSteamID64    uint64                `json:"callerSteamId"`
+	TargetName         string                `json:"targetName"` // Kicked player or map name
+	TargetSteamID64    uint64                `json:"targetSteamId"`
+	YesCount           int                   `json:"yesCount"`
+	NoCount            int                   `json:"noCount"`
+	PotentialVoteCount int                   `json:"potentialVoteCount"`
+	Outcome            constants.VoteOutcome `json:"outcome"`
+	FailReason         int32                 `json:"failReason"` // Raw vote_create_failed_t value sent by the game
+}
+
+// The team sent with vote user messages is -1 when all players are allowed to vote.
+func voteSideFromTeam(team int32) common.Team {
+	if team == int32(common.TeamTerrorists) || team == int32(common.TeamCounterTerrorists) {
+		return common.Team(team)
+	}
+
+	return common.TeamUnassigned
+}
+
+func (analyzer *Analyzer) playerFromVoteSlot(slot int32) *common.Player {
+	if slot < 0 {
+		return nil
+	}
+
+	return analyzer.parser.GameState().Participants().ByEntityID()[int(slot)+1]
+}
+
+func (analyzer *Analyzer) voteOptionCount(option int) int {
+	entity := analyzer.voteControllerEntity
+	if entity == nil {
+		return 0
+	}
+
+	// Arrays props are suffixed with a 4 digits index with CS2 demos and 3 digits with CSGO demos.
+	if value, exists := entity.PropertyValue(fmt.Sprintf("m_nVoteOptionCount.%04d", option)); exists {
+		return propertyValueToInt(value)
+	}
+	if value, exists := entity.PropertyValue(fmt.Sprintf("m_nVoteOptionCount.%03d", option)); exists {
+		return propertyValueToInt(value)
+	}
+
+	return 0
+}
+
+func (analyzer *Analyzer) registerVoteStart(message *msg.CCSUsrMsg_VoteStart) {
+	voteType, isKnownType := constants.VoteTypeByIssue[message.GetVoteType()]
+	if !isKnownType {
+		voteType = constants.VoteTypeUnknown
+	}
+
+	vote := &Vote{
+		Frame:       analyzer.parser.CurrentFrame(),
+		Tick:        analyzer.currentTick(),
+		RoundNumber: analyzer.currentRound.Number,
+		Type:        voteType,
+		Side:        voteSideFromTeam(message.GetTeam()),
+		TargetName:  message.GetDetailsStr(),
+		Outcome:     constants.VoteOutcomePending,
+	}
+	if caller := analyzer.playerFromVoteSlot(message.GetPlayerSlot()); caller != nil {
+		vote.CallerName = caller.Name
+		vote.CallerSteamID64 = caller.SteamID64
+	}
+	if target := analyzer.playerFromVoteSlot(message.GetPlayerSlotTarget()); target != nil {
+		vote.TargetName = target.Name
+		vote.TargetSteamID64 = target.SteamID64
+	}
+
+	analyzer.match.Votes = append(analyzer.match.Votes, vote)
+	analyzer.currentVote = vote
+}
+
+func (analyzer *Analyzer) registerVoteEnd(outcome constants.VoteOutcome, failReason int32) *Vote {
+	vote := analyzer.currentVote
+	if vote == nil {
+		return nil
+	}
+
+	vote.EndFrame = analyzer.parser.CurrentFrame()
+	vote.EndTick = analyzer.currentTick()
+	vote.Outcome = outcome
+	vote.FailReason = failReason
+	vote.YesCount = analyzer.voteOptionCount(0)
+	vote.NoCount = analyzer.voteOptionCount(1)
+	if entity := analyzer.voteControllerEntity; entity != nil {
+		if value, exists := entity.PropertyValue("m_nPotentialVotes"); exists {
+			vote.PotentialVoteCount = propertyValueToInt(value)
+		}
+	}
+	analyzer.currentVote = nil
+
+	return vote
+}
+
+func (analyzer *Analyzer) registerVotePass() {
+	vote := analyzer.registerVoteEnd(constants.VoteOutcomePassed, 0)
+	if vote != nil && vote.Type == constants.VoteTypeSurrender {
+		analyzer.match.EndedBySurrender = true
+	}
+}
+
+func (analyzer *Analyzer) registerVoteHandlers() {
+	parser := analyzer.parser
+	parser.RegisterEventHandler(func(event events.DataTablesParsed) {
+		if voteControllerClass := parser.ServerClasses().FindByName("CVoteController"); voteControllerClass != nil {
+			voteControllerClass.OnEntityCreated(func(entity st.Entity) {
+				analyzer.voteControllerEntity = entity
+			})
+		}
+	})
+
+	parser.RegisterNetMessageHandler(func(message *msg.CCSUsrMsg_VoteStart) {
+		analyzer.registerVoteStart(message)
+	})
+
+	parser.RegisterNetMessageHandler(func(message *msg.CCSUsrMsg_VotePass) {
+		analyzer.registerVotePass()
+	})
+
+	parser.RegisterNetMessageHandler(func(message *msg.CCSUsrMsg_VoteFailed) {
+		analyzer.registerVoteEnd(constants.VoteOutcomeFailed, message.GetReason())
+	})
+}
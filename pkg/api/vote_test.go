@@ -0,0 +1,87 @@
+package api
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/akiver/cs-demo-analyzer/pkg/api/constants"
+	dem "github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs"
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/common"
+	"github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/msg"
+)
+
+func newVoteTestAnalyzer() *Analyzer {
+	return &Analyzer{
+		// The parser reads the beginning of the stream when it is created.
+		parser:       dem.NewParser(bytes.NewReader(make([]byte, 1024))),
+		match:        &Match{Votes: []*Vote{}},
+		currentRound: &Round{Number: 7},
+	}
+}
+
+func newVoteStartMessage(issue int32, team int32, details string) *msg.CCSUsrMsg_VoteStart {
+	noPlayerSlot := int32(-1)
+	return &msg.CCSUsrMsg_VoteStart{
+		Team:             &team,
+		PlayerSlot:       &noPlayerSlot,
+		VoteType:         &issue,
+		DetailsStr:       &details,
+		PlayerSlotTarget: &noPlayerSlot,
+	}
+}
+
+func TestVote_SurrenderPassed(t *testing.T) {
+	analyzer := newVoteTestAnalyzer()
+	analyzer.registerVoteStart(newVoteStartMessage(6, int32(common.TeamTerrorists), ""))
+
+	vote := analyzer.match.Votes[0]
+	if vote.Type != constants.VoteTypeSurrender || vote.Side != common.TeamTerrorists || vote.RoundNumber != 7 || vote.Outcome != constants.VoteOutcomePending {
+		t.Fatalf("unexpected vote %+v", vote)
+	}
+
+	analyzer.registerVotePass()
+	if vote.Outcome != constants.VoteOutcomePassed {
+		t.Errorf("expected the vote to pass got %s", vote.Outcome)
+	}
+	if !analyzer.match.EndedBySurrender {
+		t.Errorf("expected the match to be ended by a surrender")
+	}
+	if analyzer.currentVote != nil {
+		t.Errorf("expected no vote in progress")
+	}
+}
+
+func TestVote_FailedSurrenderAndOtherPassedVotes(t *testing.T) {
+	analyzer := newVoteTestAnalyzer()
+	analyzer.registerVoteStart(newVoteStartMessage(6, int32(common.TeamCounterTerrorists), ""))
+	analyzer.registerVoteEnd(constants.VoteOutcomeFailed, 3)
+
+	analyzer.registerVoteStart(newVoteStartMessage(1, -1, "de_inferno"))
+	analyzer.registerVotePass()
+
+	// A vote without start message is ignored.
+	analyzer.registerVotePass()
+
+	if analyzer.match.EndedBySurrender {
+		t.Errorf("expected the match not to be ended by a surrender")
+	}
+	if len(analyzer.match.Votes) != 2 {
+		t.Fatalf("expected 2 votes got %d", len(analyzer.match.Votes))
+	}
+	surrender, changeLevel := analyzer.match.Votes[0], analyzer.match.Votes[1]
+	if surrender.Outcome != constants.VoteOutcomeFailed || surrender.FailReason != 3 {
+		t.Errorf("expected a failed surrender got %+v", surrender)
+	}
+	if changeLevel.Type != constants.VoteTypeChangeLevel || changeLevel.Side != common.TeamUnassigned || changeLevel.TargetName != "de_inferno" || changeLevel.Outcome != constants.VoteOutcomePassed {
+		t.Errorf("expected a passed change level vote got %+v", changeLevel)
+	}
+}
+
+func TestVote_UnknownIssue(t *testing.T) {
+	analyzer := newVoteTestAnalyzer()
+	analyzer.registerVoteStart(newVoteStartMessage(99, -1, ""))
+
+	if analyzer.match.Votes[0].Type != constants.VoteTypeUnknown {
+		t.Errorf("expected an unknown vote type got %s", analyzer.match.Votes[0].Type)
+	}
+}
@@ -42,10 +42,11 @@ func markHeuristicWallbangDamages(match *Match) {
 		positionIndex = buildWallbangHeuristicPositionIndex(match.PlayerPositions)
 	}
 
+	config := match.config()
 	for _, damage := range match.Damages {
 		isTrueWallbang := damage.isWallbang()
 		if haveHeuristicData {
-			damage.IsWallbang = isTrueWallbang || isSuspectedWallbangDamage(damage, shotIndex, positionIndex)
+			damage.IsWallbang = isTrueWallbang || isSuspectedWallbangDamage(damage, shotIndex, positionIndex, config)
 			continue
 		}
 
@@ -53,7 +54,7 @@ func markHeuristicWallbangDamages(match *Match) {
 	}
 }
 
-func isSuspectedWallbangDamage(damage *Damage, shotIndex map[wallbangShotKey][]shotIndexEntry, positionIndex map[wallbangPositionKey][]*PlayerPosition) bool {
+func isSuspectedWallbangDamage(damage *Damage, shotIndex map[wallbangShotKey][]shotIndexEntry, positionIndex map[wallbangPositionKey][]*PlayerPosition, config AnalyzerConfig) bool {
 	if damage == nil {
 		return false
 	}
@@ -71,22 +72,22 @@ func isSuspectedWallbangDamage(damage *Damage, shotIndex map[wallbangShotKey][]s
 		return false
 	}
 
-	if !constants.HeuristicWallbangIncludeHeadshots && isHeadHit(damage.HitGroup) {
+	if !config.WallbangIncludeHeadshots && isHeadHit(damage.HitGroup) {
 		return false
 	}
 
-	matchedShot := nearestShotForWallbangDamage(damage, shotIndex)
+	matchedShot := nearestShotForWallbangDamage(damage, shotIndex, config.DamageAttributionMaxShotFrameDistance)
 	if matchedShot == nil {
 		return false
 	}
 
-	victimPosition := nearestVictimPositionForWallbangDamage(damage, positionIndex)
+	victimPosition := nearestVictimPositionForWallbangDamage(damage, positionIndex, config.WallbangMaxVictimPositionFrameDelta)
 	distance := 0.0
 	if victimPosition != nil {
 		distance = euclideanDistance3D(matchedShot.X, matchedShot.Y, matchedShot.Z, victimPosition.X, victimPosition.Y, victimPosition.Z)
 	}
 
-	victimHasHelmet := resolveVictimHasHelmet(damage, victimPosition)
+	victimHasHelmet := resolveVictimHasHelmet(damage, victimPosition, config.WallbangHelmetSnapshotFrameWindow)
 	expected := expectedHealthDamageHeuristic(model, damage.HitGroup, distance, damage.VictimArmor, victimHasHelmet)
 	expected = capExpectedHealthDamage(expected, damage.VictimHealth)
 	observed := float64(damage.HealthDamage)
@@ -96,12 +97,12 @@ func isSuspectedWallbangDamage(damage *Damage, shotIndex map[wallbangShotKey][]s
 		return false
 	}
 
-	if delta < constants.HeuristicWallbangMinDelta {
+	if delta < config.WallbangMinDelta {
 		return false
 	}
 
 	lossRatio := delta / expected
-	if lossRatio < constants.HeuristicWallbangMinLossRatio {
+	if lossRatio < config.WallbangMinLossRatio {
 		return false
 	}
 
@@ -156,7 +157,7 @@ func buildWallbangHeuristicPositionIndex(positions []*PlayerPosition) map[wallba
 	return index
 }
 
-func nearestShotForWallbangDamage(damage *Damage, shotIndex map[wallbangShotKey][]shotIndexEntry) *Shot {
+func nearestShotForWallbangDamage(damage *Damage, shotIndex map[wallbangShotKey][]shotIndexEntry, maxFrameDistance int) *Shot {
 	key := wallbangShotKey{round: damage.RoundNumber, attacker: damage.AttackerSteamID64, weaponID: damage.WeaponUniqueID}
 	entries, exists := shotIndex[key]
 	if !exists || len(entries) == 0 {
@@ -176,7 +177,7 @@ func nearestShotForWallbangDamage(damage *Damage, shotIndex map[wallbangShotKey]
 		}
 
 		frameDelta := absInt(entry.frame - damage.Frame)
-		if frameDelta > constants.HeuristicDamageAttributionMaxShotFrameDistance {
+		if frameDelta > maxFrameDistance {
 			continue
 		}
 
@@ -191,7 +192,7 @@ func nearestShotForWallbangDamage(damage *Damage, shotIndex map[wallbangShotKey]
 	return best
 }
 
-func nearestVictimPositionForWallbangDamage(damage *Damage, positionIndex map[wallbangPositionKey][]*PlayerPosition) *PlayerPosition {
+func nearestVictimPositionForWallbangDamage(damage *Damage, positionIndex map[wallbangPositionKey][]*PlayerPosition, maxFrameDelta int) *PlayerPosition {
 	key := wallbangPositionKey{round: damage.RoundNumber, steamID: damage.VictimSteamID64}
 	positions, exists := positionIndex[key]
 	if !exists || len(positions) == 0 {
@@ -211,7 +212,7 @@ func nearestVictimPositionForWallbangDamage(damage *Damage, positionIndex map[wa
 		}
 
 		frameDelta := absInt(position.Frame - damage.Frame)
-		if frameDelta > constants.HeuristicWallbangMaxVictimPositionFrameDelta {
+		if frameDelta > maxFrameDelta {
 			continue
 		}
 
@@ -247,7 +248,7 @@ func expectedHealthDamageHeuristic(model constants.HeuristicWallbangWeaponDamage
 	return clampFloat(healthDamage, 0, postHitgroup)
 }
 
-func resolveVictimHasHelmet(damage *Damage, victimPosition *PlayerPosition) bool {
+func resolveVictimHasHelmet(damage *Damage, victimPosition *PlayerPosition, snapshotFrameWindow int) bool {
 	if !isHeadHit(damage.HitGroup) {
 		if victimPosition != nil {
 			return victimPosition.HasHelmet
@@ -256,7 +257,7 @@ func resolveVictimHasHelmet(damage *Damage, victimPosition *PlayerPosition) bool
 		return false
 	}
 
-	if victimPosition != nil && absInt(damage.Frame-victimPosition.Frame) <= constants.HeuristicWallbangHelmetSnapshotFrameWindow {
+	if victimPosition != nil && absInt(damage.Frame-victimPosition.Frame) <= snapshotFrameWindow {
 		return victimPosition.HasHelmet
 	}
 
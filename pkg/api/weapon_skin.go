@@ -0,0 +1,168 @@
+package api
+
+import (
+	"fmt"
+	stdmath "math"
+	"strings"
+
+	st "github.com/markus-wa/demoinfocs-golang/v5/pkg/demoinfocs/sendtables"
+)
+
+// Econ item attribute definition indexes, see items_game.txt.
+const (
+	attributePaintKit        = 6
+	attributePaintSeed       = 7
+	attributePaintWear       = 8
+	attributeStatTrakCounter = 80
+	attributeStickerSlot0ID  = 113 // Each sticker slot uses 4 attributes: id, wear, scale and rotation
+	maxStickerSlotCount      = 6
+	maxItemAttributeCount    = 32
+)
+
+type WeaponSticker struct {
+	Slot int     `json:"slot"`
+	ID   int     `json:"id"` // Sticker kit definition index
+	Wear float32 `json:"wear"`
+}
+
+type WeaponSkin struct {
+	ItemDefinitionIndex int             `json:"itemDefinitionIndex"`
+	PaintKit            int             `json:"paintKit"` // Paint kit definition index, 0 for vanilla items
+	Wear                float32         `json:"wear"`
+	Seed                int             `json:"seed"`
+	StatTrak            int             `json:"statTrak"` // -1 if the item is not StatTrak
+	CustomName          string          `json:"customName"`
+	Stickers            []WeaponSticker `json:"stickers"`
+}
+
+// Exterior name of the skin based on its wear, e.g. "FT" for Field-Tested.
+func (skin *WeaponSkin) WearName() string {
+	if skin.PaintKit == 0 {
+		return ""
+	}
+
+	switch {
+	case skin.Wear < 0.07:
+		return "FN"
+	case skin.Wear < 0.15:
+		return "MW"
+	case skin.Wear < 0.38:
+		return "FT"
+	case skin.Wear < 0.45:
+		return "WW"
+	default:
+		return "BS"
+	}
+}
+
+// Stickers formatted as "slot:id:wear" separated by ";" for CSV exports.
+func (skin *WeaponSkin) StickersString() string {
+	parts := make([]string, 0, len(skin.Stickers))
+	for _, sticker := range skin.Stickers {
+		parts = append(parts, fmt.Sprintf("%d:%d:%g", sticker.Slot, sticker.ID, sticker.Wear))
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// Econ item props are nested under these prefixes in weapon and player pawn entities.
+const (
+	weaponEconItemPrefix = "m_AttributeManager.m_Item."
+	glovesEconItemPrefix = "m_EconGloves."
+)
+
+func getEntityPropertyValue(entity st.Entity, names ...string) (st.PropertyValue, bool) {
+	for _, name := range names {
+		if value, exists := entity.PropertyValue(name); exists && value.Any != nil {
+			return value, true
+		}
+	}
+
+	return st.PropertyValue{}, false
+}
+
+// Attributes values are sent as float32, integer attributes such as sticker ids are stored in the float bits.
+func readItemAttributes(entity st.Entity, itemPrefix string) map[int]float32 {
+	attributes := make(map[int]float32)
+	for index := range maxItemAttributeCount {
+		attributePrefix := fmt.Sprintf("%sm_NetworkedDynamicAttributes.m_Attributes.%04d.", itemPrefix, index)
+		definitionIndex, exists := getEntityPropertyValue(entity, attributePrefix+"m_iAttributeDefinitionIndex")
+		if !exists {
+			break
+		}
+		value, exists := getEntityPropertyValue(entity, attributePrefix+"m_flValue", attributePrefix+"m_flInitialValue")
+		if !exists {
+			continue
+		}
+		if floatValue, isFloat := value.Any.(float32); isFloat {
+			attributes[propertyValueToInt(definitionIndex)] = floatValue
+		}
+	}
+
+	return attributes
+}
+
+func newWeaponSkinFromEntity(entity st.Entity, itemPrefix string) *WeaponSkin {
+	if entity == nil {
+		return nil
+	}
+
+	definitionIndex, exists := getEntityPropertyValue(entity, itemPrefix+"m_iItemDefinitionIndex", "m_iItemDefinitionIndex")
+	if !exists {
+		return nil
+	}
+
+	skin := &WeaponSkin{
+		ItemDefinitionIndex: propertyValueToInt(definitionIndex),
+		StatTrak:            -1,
+		Stickers:            []WeaponSticker{},
+	}
+	if customName, exists := getEntityPropertyValue(entity, itemPrefix+"m_szCustomName"); exists {
+		skin.CustomName = customName.String()
+	}
+
+	attributes := readItemAttributes(entity, itemPrefix)
+	if paintKit, exists := attributes[attributePaintKit]; exists {
+		skin.PaintKit = int(paintKit)
+	}
+	if seed, exists := attributes[attributePaintSeed]; exists {
+		skin.Seed = int(seed)
+	}
+	if wear, exists := attributes[attributePaintWear]; exists {
+		skin.Wear = wear
+	}
+	if statTrak, exists := attributes[attributeStatTrakCounter]; exists {
+		skin.StatTrak = int(stdmath.Float32bits(statTrak))
+	}
+	for slot := range maxStickerSlotCount {
+		stickerID, exists := attributes[attributeStickerSlot0ID+slot*4]
+		if !exists {
+			continue
+		}
+		skin.Stickers = append(skin.Stickers, WeaponSticker{
+			Slot: slot,
+			ID:   int(stdmath.Float32bits(stickerID)),
+			Wear: attributes[attributeStickerSlot0ID+slot*4+1],
+		})
+	}
+
+	// Fallback props are used by community servers and for items without networked attributes.
+	if skin.PaintKit == 0 {
+		if paintKit, exists := getEntityPropertyValue(entity, "m_nFallbackPaintKit"); exists {
+			skin.PaintKit = propertyValueToInt(paintKit)
+		}
+		if seed, exists := getEntityPropertyValue(entity, "m_nFallbackSeed"); exists {
+			skin.Seed = propertyValueToInt(seed)
+		}
+		if wear, exists := getEntityPropertyValue(entity, "m_flFallbackWear"); exists {
+			skin.Wear = float32(propertyValueToFloat64(wear))
+		}
+	}
+	if skin.StatTrak == -1 {
+		if statTrak, exists := getEntityPropertyValue(entity, "m_nFallbackStatTrak"); exists && propertyValueToInt(statTrak) >= 0 {
+			skin.StatTrak = propertyValueToInt(statTrak)
+		}
+	}
+
+	return skin
+}
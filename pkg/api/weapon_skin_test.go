@@ -0,0 +1,31 @@
+package api
+
+import "testing"
+
+func TestWeaponSkin_WearName(t *testing.T) {
+	samples := map[float32]string{
+		0.01: "FN",
+		0.07: "MW",
+		0.2:  "FT",
+		0.4:  "WW",
+		0.9:  "BS",
+	}
+	for wear, expected := range samples {
+		skin := &WeaponSkin{PaintKit: 282, Wear: wear}
+		if skin.WearName() != expected {
+			t.Fatalf("expected wear %f to be %s, got %s", wear, expected, skin.WearName())
+		}
+	}
+
+	vanilla := &WeaponSkin{Wear: 0.2}
+	if vanilla.WearName() != "" {
+		t.Fatalf("expected vanilla item to not have a wear name, got %s", vanilla.WearName())
+	}
+}
+
+func TestWeaponSkin_StickersString(t *testing.T) {
+	skin := &WeaponSkin{Stickers: []WeaponSticker{{Slot: 0, ID: 4682, Wear: 0}, {Slot: 2, ID: 5923, Wear: 0.5}}}
+	if got := skin.StickersString(); got != "0:4682:0;2:5923:0.5" {
+		t.Fatalf("unexpected stickers string %q", got)
+	}
+}
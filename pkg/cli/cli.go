@@ -1,10 +1,16 @@
 package cli
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
+	"log/slog"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/akiver/cs-demo-analyzer/pkg/api"
 	"github.com/akiver/cs-demo-analyzer/pkg/api/constants"
@@ -17,6 +23,17 @@ type cliArgs struct {
 	outputPath       string
 	format           string
 	minifyJSON       bool
+	setupOffsets     string
+	voiceOutput      string
+	progress         bool
+	timeout          time.Duration
+	errorFormat      string
+	logLevel         string
+	strict           bool
+	configPath       string
+	config           *api.AnalyzerConfig // Loaded from configPath during validation
+	metricsPath      string
+	metrics          []api.MetricDefinition // Loaded from metricsPath during validation
 }
 
 func (cli *cliArgs) validateArgs() error {
@@ -28,6 +45,14 @@ func (cli *cliArgs) validateArgs() error {
 		return errors.New("output path required, example: -output ./output")
 	}
 
+	if cli.errorFormat != errorFormatText && cli.errorFormat != errorFormatJSON {
+		return fmt.Errorf("invalid error format %q, valid values: [text,json]", cli.errorFormat)
+	}
+
+	if _, err := cli.parseLogLevel(); err != nil {
+		return err
+	}
+
 	if cli.format != "" {
 		err := api.ValidateExportFormat(constants.ExportFormat(cli.format))
 		if err != nil {
@@ -42,25 +67,95 @@ func (cli *cliArgs) validateArgs() error {
 		}
 	}
 
+	if _, err := cli.parseSetupOffsets(); err != nil {
+		return err
+	}
+
+	if cli.configPath != "" {
+		config, err := api.LoadAnalyzerConfig(cli.configPath)
+		if err != nil {
+			return err
+		}
+		cli.config = &config
+	}
+
+	if cli.metricsPath != "" {
+		metrics, err := api.LoadMetricDefinitions(cli.metricsPath)
+		if err != nil {
+			return err
+		}
+		cli.metrics = metrics
+	}
+
 	return nil
 }
 
+func (cli *cliArgs) parseSetupOffsets() ([]float64, error) {
+	if cli.setupOffsets == "" {
+		return nil, nil
+	}
+
+	offsets := []float64{}
+	for _, value := range strings.Split(cli.setupOffsets, ",") {
+		offset, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || offset < 0 {
+			return nil, fmt.Errorf("invalid setup offset %q, example: -setup-offsets 10,20,40", value)
+		}
+		offsets = append(offsets, offset)
+	}
+
+	return offsets, nil
+}
+
+func (cli *cliArgs) parseLogLevel() (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cli.logLevel)); err != nil {
+		return level, fmt.Errorf("invalid log level %q, valid values: [debug,info,warn,error]", cli.logLevel)
+	}
+
+	return level, nil
+}
+
+// Logs are written to stderr to keep stdout free, as JSON when errors are printed as JSON.
+func (cli *cliArgs) newLogger() *slog.Logger {
+	level, _ := cli.parseLogLevel()
+	handlerOptions := &slog.HandlerOptions{Level: level}
+	if cli.errorFormat == errorFormatJSON {
+		return slog.New(slog.NewJSONHandler(os.Stderr, handlerOptions))
+	}
+
+	return slog.New(slog.NewTextHandler(os.Stderr, handlerOptions))
+}
+
 func (cli *cliArgs) fromArgs(args []string) error {
 	fs := flag.NewFlagSet("csda", flag.ContinueOnError)
-	fs.StringVar(&cli.demoPath, "demo-path", "", "Demo file path (mandatory)")
+	fs.StringVar(&cli.demoPath, "demo-path", "", "Demo file path, use - to read the demo from stdin, gzip/bzip2/zstd/zip compressed demos are supported (mandatory)")
 	fs.StringVar(&cli.outputPath, "output", "", "Output folder or file path, must be a folder when exporting to CSV (mandatory)")
 	fs.StringVar(&cli.format, "format", "csv", "Export format, valid values: "+api.FormatValidExportFormats())
 	fs.StringVar(&cli.source, "source", "", "Force demo's source, valid values: "+api.FormatValidDemoSources())
 	fs.BoolVar(&cli.includePositions, "positions", false, "Include entities (players, grenades...) positions (default false)")
 	fs.BoolVar(&cli.minifyJSON, "minify", false, "Minify JSON file, it has effect only when -format is set to json")
+	fs.StringVar(&cli.setupOffsets, "setup-offsets", "", "Comma-separated seconds after the freeze time end at which teams setup are captured (default 10,20,40)")
+	fs.StringVar(&cli.voiceOutput, "voice-output", "", "Folder where players voice is written as Ogg Opus files per round, CS2 demos only")
+	fs.BoolVar(&cli.progress, "progress", false, "Print progress lines as JSON to stderr, example: {\"progress\":0.42,\"round\":12}")
+	fs.DurationVar(&cli.timeout, "timeout", 0, "Stop the analysis if it takes longer than the given duration, example: 2m (default no timeout)")
+	fs.StringVar(&cli.configPath, "config", "", "YAML or JSON (.json extension) file overriding the heuristics thresholds, the effective config is exported next to the CSV files")
+	fs.StringVar(&cli.metricsPath, "metrics", "", "YAML or JSON (.json extension) file defining derived metrics exported as extra CSV tables and _players.csv columns")
+	fs.BoolVar(&cli.strict, "strict", false, "Exit with an error if a validation check failed, the demo is still exported")
+	fs.StringVar(&cli.logLevel, "log-level", "warn", "Minimum level of the logs printed to stderr, analysis warnings are logged at the warn level, valid values: [debug,info,warn,error]")
+	fs.StringVar(&cli.errorFormat, "error-format", errorFormatText, "Errors output format, json prints {\"code\":\"DemoNotFound\",\"message\":\"...\"} to stderr, valid values: [text,json]")
 
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
 
 	if err := cli.validateArgs(); err != nil {
-		fmt.Fprintf(os.Stderr, "%v\n", err)
-		fs.Usage()
+		if cli.errorFormat == errorFormatJSON {
+			printError(err, api.ErrorCodeInvalidArgument, cli.errorFormat)
+		} else {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			fs.Usage()
+		}
 		return err
 	}
 
@@ -68,22 +163,58 @@ func (cli *cliArgs) fromArgs(args []string) error {
 }
 
 func Run(args []string) int {
+	if len(args) > 0 {
+		switch args[0] {
+		case "sharecode":
+			return runShareCode(args[1:])
+		case "inspect":
+			return runInspect(args[1:])
+		case "diff":
+			return runDiff(args[1:])
+		}
+	}
+
 	var cli cliArgs
 	err := cli.fromArgs(args)
 	if err != nil {
 		return 2
 	}
 
-	err = api.AnalyzeAndExportDemo(cli.demoPath, cli.outputPath, api.AnalyzeAndExportDemoOptions{
-		IncludePositions: cli.includePositions,
-		Source:           constants.DemoSource(cli.source),
-		Format:           constants.ExportFormat(cli.format),
-		MinifyJSON:       cli.minifyJSON,
-	})
+	setupOffsets, _ := cli.parseSetupOffsets()
+	options := api.AnalyzeAndExportDemoOptions{
+		IncludePositions:     cli.includePositions,
+		Source:               constants.DemoSource(cli.source),
+		Format:               constants.ExportFormat(cli.format),
+		MinifyJSON:           cli.minifyJSON,
+		SetupSnapshotOffsets: setupOffsets,
+		VoiceOutputFolder:    cli.voiceOutput,
+		Logger:               cli.newLogger(),
+		Strict:               cli.strict,
+		Config:               cli.config,
+		Metrics:              cli.metrics,
+	}
+	if cli.progress {
+		options.OnProgress = func(fraction float64, round int) {
+			fmt.Fprintf(os.Stderr, "{\"progress\":%.4f,\"round\":%d}\n", fraction, round)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if cli.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cli.timeout)
+		defer cancel()
+	}
+
+	if cli.demoPath == "-" {
+		err = api.AnalyzeAndExportDemoFromReaderContext(ctx, os.Stdin, "stdin", cli.outputPath, options)
+	} else {
+		err = api.AnalyzeAndExportDemoContext(ctx, cli.demoPath, cli.outputPath, options)
+	}
 
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "%v\n", err)
-		return 1
+		return printError(err, api.GetErrorCode(err), cli.errorFormat)
 	}
 
 	return 0
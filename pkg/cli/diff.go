@@ -0,0 +1,231 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/akiver/cs-demo-analyzer/pkg/api"
+	"github.com/akiver/cs-demo-analyzer/pkg/api/constants"
+)
+
+// Maximum number of rows listed per table and kind of change in the text output.
+const diffMaxListedRows = 10
+
+type diffArgs struct {
+	format      string
+	minifyJSON  bool
+	ignore      string
+	source      string
+	configPathA string
+	configPathB string
+	errorFormat string
+	paths       []string
+}
+
+func (cli *diffArgs) fromArgs(args []string) error {
+	fs := flag.NewFlagSet("csda diff", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage:\n  csda diff [options] a.json b.json\n  csda diff [options] -config-a a.yaml -config-b b.yaml demo.dem\n")
+		fs.PrintDefaults()
+	}
+	fs.StringVar(&cli.format, "format", "text", "Output format, valid values: [text,json]")
+	fs.BoolVar(&cli.minifyJSON, "minify", false, "Minify JSON output, it has effect only when -format is set to json")
+	fs.StringVar(&cli.ignore, "ignore", "", "Comma-separated fields or tables (JSON names) that are not compared, example: demoFilePath,analyzerConfig")
+	fs.StringVar(&cli.source, "source", "", "Force demo's source when a demo is given, valid values: "+api.FormatValidDemoSources())
+	fs.StringVar(&cli.configPathA, "config-a", "", "Heuristics config of the first analysis when a demo is given (default config if empty)")
+	fs.StringVar(&cli.configPathB, "config-b", "", "Heuristics config of the second analysis when a demo is given (default config if empty)")
+	fs.StringVar(&cli.errorFormat, "error-format", errorFormatText, "Errors output format, json prints {\"code\":\"DemoNotFound\",\"message\":\"...\"} to stderr, valid values: [text,json]")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	cli.paths = fs.Args()
+
+	var err error
+	switch {
+	case len(cli.paths) == 0 || len(cli.paths) > 2:
+		err = errors.New("two JSON exports or a demo required, example: csda diff a.json b.json")
+	case len(cli.paths) == 2 && (cli.configPathA != "" || cli.configPathB != "" || cli.source != ""):
+		err = errors.New("-config-a, -config-b and -source can only be used with a demo")
+	case len(cli.paths) == 1 && cli.configPathA == "" && cli.configPathB == "":
+		err = errors.New("-config-a or -config-b required to compare two analyses of a demo")
+	case cli.format != "text" && cli.format != "json":
+		err = fmt.Errorf("invalid format %q, valid values: [text,json]", cli.format)
+	case cli.errorFormat != errorFormatText && cli.errorFormat != errorFormatJSON:
+		err = fmt.Errorf("invalid error format %q, valid values: [text,json]", cli.errorFormat)
+	case cli.source != "":
+		err = api.ValidateDemoSource(constants.DemoSource(cli.source))
+	}
+	if err != nil {
+		if cli.errorFormat == errorFormatJSON {
+			printError(err, api.ErrorCodeInvalidArgument, cli.errorFormat)
+		} else {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			fs.Usage()
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (cli *diffArgs) options() api.DiffOptions {
+	options := api.DiffOptions{}
+	for _, field := range strings.Split(cli.ignore, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			options.IgnoredFields = append(options.IgnoredFields, field)
+		}
+	}
+
+	return options
+}
+
+func (cli *diffArgs) analyze(configPath string) (*api.Match, error) {
+	options := api.AnalyzeDemoOptions{
+		Source: constants.DemoSource(cli.source),
+	}
+	if configPath != "" {
+		config, err := api.LoadAnalyzerConfig(configPath)
+		if err != nil {
+			return nil, err
+		}
+		options.Config = &config
+	}
+
+	return api.AnalyzeDemo(cli.paths[0], options)
+}
+
+// The demo is analyzed twice, the analyzer config is obviously different.
+func (cli *diffArgs) diffDemo() (*api.MatchDiff, error) {
+	matchA, err := cli.analyze(cli.configPathA)
+	if err != nil {
+		return nil, err
+	}
+	matchB, err := cli.analyze(cli.configPathB)
+	if err != nil {
+		return nil, err
+	}
+
+	options := cli.options()
+	options.IgnoredFields = append(options.IgnoredFields, "analyzerConfig")
+
+	return api.DiffMatches(matchA, matchB, options)
+}
+
+func formatDiffValue(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(data)
+}
+
+func writeDiffKeys(writer io.Writer, prefix string, keys []string) {
+	for index, key := range keys {
+		if index == diffMaxListedRows {
+			fmt.Fprintf(writer, "    %s ... %d more\n", prefix, len(keys)-index)
+			return
+		}
+		fmt.Fprintf(writer, "    %s %s\n", prefix, key)
+	}
+}
+
+func writeDiffText(writer io.Writer, diff *api.MatchDiff) {
+	if !diff.HasChanges() {
+		fmt.Fprintln(writer, "No differences")
+		return
+	}
+
+	if len(diff.Fields) > 0 {
+		fmt.Fprintln(writer, "Fields:")
+		for _, change := range diff.Fields {
+			fmt.Fprintf(writer, "  %s: %s -> %s\n", change.Field, formatDiffValue(change.A), formatDiffValue(change.B))
+		}
+	}
+
+	if len(diff.Tables) > 0 {
+		fmt.Fprintln(writer, "Tables:")
+	}
+	for _, table := range diff.Tables {
+		fmt.Fprintf(writer, "  %s: %d -> %d rows, %d added, %d removed, %d changed\n", table.Table, table.RowCountA, table.RowCountB, len(table.Added), len(table.Removed), len(table.Changed))
+		if len(table.ChangedFields) > 0 {
+			fields := []string{}
+			for field := range table.ChangedFields {
+				fields = append(fields, field)
+			}
+			slices.Sort(fields)
+			for index, field := range fields {
+				fields[index] = fmt.Sprintf("%s (%d)", field, table.ChangedFields[field])
+			}
+			fmt.Fprintf(writer, "    changed fields: %s\n", strings.Join(fields, ", "))
+		}
+		writeDiffKeys(writer, "+", table.Added)
+		writeDiffKeys(writer, "-", table.Removed)
+		changedKeys := []string{}
+		for _, row := range table.Changed {
+			changes := []string{}
+			for _, change := range row.Changes {
+				changes = append(changes, fmt.Sprintf("%s %s -> %s", change.Field, formatDiffValue(change.A), formatDiffValue(change.B)))
+			}
+			changedKeys = append(changedKeys, row.Key+": "+strings.Join(changes, ", "))
+		}
+		writeDiffKeys(writer, "~", changedKeys)
+	}
+}
+
+// Like diff, 1 means that the analyses are different, errors that would exit with 1 exit with 2 instead.
+func printDiffError(err error, code api.ErrorCode, format string) int {
+	exitCode := printError(err, code, format)
+	if exitCode < 2 {
+		return 2
+	}
+
+	return exitCode
+}
+
+// Compares two analyses, the exit code is 0 without differences, 1 otherwise like diff and 2 or more on errors.
+func runDiff(args []string) int {
+	var cli diffArgs
+	if err := cli.fromArgs(args); err != nil {
+		return 2
+	}
+
+	var diff *api.MatchDiff
+	var err error
+	if len(cli.paths) == 2 {
+		diff, err = api.DiffMatchFiles(cli.paths[0], cli.paths[1], cli.options())
+	} else {
+		diff, err = cli.diffDemo()
+	}
+	if err != nil {
+		return printDiffError(err, api.GetErrorCode(err), cli.errorFormat)
+	}
+
+	if cli.format == "json" {
+		var jsonString []byte
+		if cli.minifyJSON {
+			jsonString, err = json.Marshal(diff)
+		} else {
+			jsonString, err = json.MarshalIndent(diff, "", "  ")
+		}
+		if err != nil {
+			return printDiffError(err, api.ErrorCodeInternal, cli.errorFormat)
+		}
+		fmt.Println(string(jsonString))
+	} else {
+		writeDiffText(os.Stdout, diff)
+	}
+
+	if diff.HasChanges() {
+		return 1
+	}
+
+	return 0
+}
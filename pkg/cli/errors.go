@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/akiver/cs-demo-analyzer/pkg/api"
+)
+
+const (
+	errorFormatText = "text"
+	errorFormatJSON = "json"
+)
+
+var exitCodeByErrorCode = map[api.ErrorCode]int{
+	api.ErrorCodeUnknown:                     1,
+	api.ErrorCodeInvalidArgument:             2,
+	api.ErrorCodeDemoNotFound:                3,
+	api.ErrorCodeCorruptedDemo:               4,
+	api.ErrorCodeGameNotSupported:            5,
+	api.ErrorCodeUnknownSource:               6,
+	api.ErrorCodeSourceNotSupported:          7,
+	api.ErrorCodePOVNotSupported:             8,
+	api.ErrorCodeMissingGameEventDescriptors: 9,
+	api.ErrorCodeCanceled:                    10,
+	api.ErrorCodeTimeout:                     11,
+	api.ErrorCodeInternal:                    12,
+	api.ErrorCodeValidationFailed:            13,
+}
+
+func getExitCode(code api.ErrorCode) int {
+	if exitCode, exists := exitCodeByErrorCode[code]; exists {
+		return exitCode
+	}
+
+	return 1
+}
+
+type errorJSON struct {
+	Code    api.ErrorCode `json:"code"`
+	Message string        `json:"message"`
+}
+
+// Prints the error to stderr and returns the exit code matching the error code.
+func printError(err error, code api.ErrorCode, format string) int {
+	if format == errorFormatJSON {
+		data, _ := json.Marshal(errorJSON{Code: code, Message: err.Error()})
+		fmt.Fprintf(os.Stderr, "%s\n", data)
+	} else {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		var apiError *api.Error
+		if errors.As(err, &apiError) && len(apiError.Stack) > 0 {
+			fmt.Fprintf(os.Stderr, "%s\n", apiError.Stack)
+		}
+	}
+
+	return getExitCode(code)
+}
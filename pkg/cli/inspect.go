@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/akiver/cs-demo-analyzer/internal/converters"
+	"github.com/akiver/cs-demo-analyzer/pkg/api"
+)
+
+type inspectArgs struct {
+	format     string
+	minifyJSON bool
+	demoPaths  []string
+}
+
+func (cli *inspectArgs) fromArgs(args []string) error {
+	fs := flag.NewFlagSet("csda inspect", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage:\n  csda inspect [options] demo1.dem demo2.dem...\n")
+		fs.PrintDefaults()
+	}
+	fs.StringVar(&cli.format, "format", "json", "Output format, valid values: [json,csv]")
+	fs.BoolVar(&cli.minifyJSON, "minify", false, "Minify JSON output, it has effect only when -format is set to json")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	cli.demoPaths = fs.Args()
+
+	var err error
+	if len(cli.demoPaths) == 0 {
+		err = errors.New("at least one demo file path required, example: csda inspect path/to/demo.dem")
+	} else if cli.format != "json" && cli.format != "csv" {
+		err = fmt.Errorf("invalid format %q, valid values: [json,csv]", cli.format)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		fs.Usage()
+		return err
+	}
+
+	return nil
+}
+
+func writeDemoHeadersCSV(headers []*api.DemoHeader) error {
+	lines := [][]string{{
+		"file path",
+		"file name",
+		"checksum",
+		"game",
+		"type",
+		"source",
+		"source rule",
+		"map",
+		"server name",
+		"client name",
+		"date",
+		"network protocol",
+		"build number",
+		"tick count",
+		"tickrate",
+		"framerate",
+		"duration",
+		"share code",
+	}}
+	for _, header := range headers {
+		lines = append(lines, []string{
+			header.FilePath,
+			header.FileName,
+			header.Checksum,
+			header.Game.String(),
+			header.Type.String(),
+			header.Source.String(),
+			header.SourceRule,
+			header.MapName,
+			header.ServerName,
+			header.ClientName,
+			header.Date.Format(time.RFC3339),
+			converters.IntToString(header.NetworkProtocol),
+			converters.IntToString(header.BuildNumber),
+			converters.IntToString(header.TickCount),
+			converters.Float64ToString(header.TickRate),
+			converters.Float64ToString(header.FrameRate),
+			converters.Float64ToString(header.Duration.Seconds()),
+			header.ShareCode,
+		})
+	}
+
+	return csv.NewWriter(os.Stdout).WriteAll(lines)
+}
+
+// Prints the header of the given demos, demos that can't be read are reported on stderr.
+func runInspect(args []string) int {
+	var cli inspectArgs
+	if err := cli.fromArgs(args); err != nil {
+		return 2
+	}
+
+	exitCode := 0
+	headers := []*api.DemoHeader{}
+	for _, demoPath := range cli.demoPaths {
+		header, err := api.InspectDemo(demoPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", demoPath, err)
+			exitCode = 1
+			continue
+		}
+		headers = append(headers, header)
+	}
+
+	if cli.format == "csv" {
+		if err := writeDemoHeadersCSV(headers); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 1
+		}
+
+		return exitCode
+	}
+
+	var jsonString []byte
+	var err error
+	if cli.minifyJSON {
+		jsonString, err = json.Marshal(headers)
+	} else {
+		jsonString, err = json.MarshalIndent(headers, "", "  ")
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+
+	fmt.Println(string(jsonString))
+
+	return exitCode
+}
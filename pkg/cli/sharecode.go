@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/akiver/cs-demo-analyzer/pkg/sharecode"
+)
+
+type shareCodeArgs struct {
+	matchID    uint64
+	outcomeID  uint64
+	token      uint
+	minifyJSON bool
+	codes      []string
+}
+
+func (cli *shareCodeArgs) fromArgs(args []string) error {
+	fs := flag.NewFlagSet("csda sharecode", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage:\n  csda sharecode [options] CSGO-xxxxx-xxxxx-xxxxx-xxxxx-xxxxx...\n  csda sharecode [options] -match-id id -outcome-id id -token token\n")
+		fs.PrintDefaults()
+	}
+	fs.Uint64Var(&cli.matchID, "match-id", 0, "Match ID to encode")
+	fs.Uint64Var(&cli.outcomeID, "outcome-id", 0, "Outcome ID (reservation ID) to encode")
+	fs.UintVar(&cli.token, "token", 0, "Token (TV port) to encode")
+	fs.BoolVar(&cli.minifyJSON, "minify", false, "Minify JSON output")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	cli.codes = fs.Args()
+
+	var err error
+	if len(cli.codes) == 0 && cli.matchID == 0 {
+		err = errors.New("share code or -match-id required, example: csda sharecode CSGO-L9spZ-ihuov-cyhtE-kxbqa-FkBAA")
+	} else if len(cli.codes) > 0 && cli.matchID != 0 {
+		err = errors.New("share codes to decode and -match-id can't be used together")
+	} else if cli.token > 0xFFFF {
+		err = errors.New("token must be lower than 65536")
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		fs.Usage()
+		return err
+	}
+
+	return nil
+}
+
+// Decodes the given share codes, or encodes the given IDs, and prints the result as a JSON array.
+func runShareCode(args []string) int {
+	var cli shareCodeArgs
+	if err := cli.fromArgs(args); err != nil {
+		return 2
+	}
+
+	shareCodes := []sharecode.ShareCode{}
+	if cli.matchID != 0 {
+		shareCodes = append(shareCodes, sharecode.ShareCode{
+			Code:      sharecode.Encode(cli.matchID, cli.outcomeID, uint32(cli.token)),
+			MatchID:   cli.matchID,
+			OutcomeID: cli.outcomeID,
+			Token:     uint32(cli.token),
+		})
+	}
+	for _, code := range cli.codes {
+		shareCode, err := sharecode.Decode(code)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", code, err)
+			return 1
+		}
+		shareCodes = append(shareCodes, shareCode)
+	}
+
+	var jsonString []byte
+	var err error
+	if cli.minifyJSON {
+		jsonString, err = json.Marshal(shareCodes)
+	} else {
+		jsonString, err = json.MarshalIndent(shareCodes, "", "  ")
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+
+	fmt.Println(string(jsonString))
+
+	return 0
+}
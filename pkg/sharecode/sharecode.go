@@ -0,0 +1,37 @@
+// Package sharecode encodes and decodes CS match share codes, e.g. CSGO-L9spZ-ihuov-cyhtE-kxbqa-FkBAA.
+package sharecode
+
+import (
+	"github.com/akiver/cs-demo-analyzer/internal/demo"
+)
+
+var ErrInvalidShareCode = demo.ErrInvalidShareCode
+
+type ShareCode struct {
+	Code      string `json:"code"`
+	MatchID   uint64 `json:"matchId"`
+	OutcomeID uint64 `json:"outcomeId"` // Also known as the reservation ID
+	Token     uint32 `json:"token"`     // Also known as the TV port, only the 16 lower bits are encoded
+}
+
+func Encode(matchID uint64, outcomeID uint64, token uint32) string {
+	return demo.EncodeMatchShareCode(demo.MatchInformation{
+		MatchId:       matchID,
+		ReservationId: outcomeID,
+		TvPort:        token,
+	})
+}
+
+func Decode(code string) (ShareCode, error) {
+	match, err := demo.DecodeMatchShareCode(code)
+	if err != nil {
+		return ShareCode{}, err
+	}
+
+	return ShareCode{
+		Code:      Encode(match.MatchId, match.ReservationId, match.TvPort),
+		MatchID:   match.MatchId,
+		OutcomeID: match.ReservationId,
+		Token:     match.TvPort,
+	}, nil
+}
@@ -0,0 +1,26 @@
+package sharecode
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	shareCode, err := Decode("CSGO-GADqf-jjyJ8-cSP2r-smZRo-TO2xK")
+	if err != nil {
+		t.Fatalf("failed to decode share code: %v", err)
+	}
+	if shareCode.MatchID != 3230642215713767580 || shareCode.OutcomeID != 3230647599455273103 || shareCode.Token != 55788 {
+		t.Fatalf("unexpected decoded share code %+v", shareCode)
+	}
+
+	if Encode(shareCode.MatchID, shareCode.OutcomeID, shareCode.Token) != shareCode.Code {
+		t.Fatalf("expected %s to be encoded back to the same share code", shareCode.Code)
+	}
+
+	for _, code := range []string{"", "CSGO-invalid", "CSGO-L9spZ-ihuov-cyhtE-kxbqa-FkBA0", "CSGO-99999-99999-99999-99999-99999"} {
+		if _, err := Decode(code); !errors.Is(err, ErrInvalidShareCode) {
+			t.Errorf("expected ErrInvalidShareCode for %q, got %v", code, err)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/akiver/cs-demo-analyzer/pkg/api"
+	"github.com/akiver/cs-demo-analyzer/pkg/api/constants"
+	"github.com/akiver/cs-demo-analyzer/tests/testsutils"
+)
+
+// Gamersclub demos have a warmup and a knife round before the live message, none of them must be analyzed as rounds.
+func TestGamersclub_2025_Mirage(t *testing.T) {
+	demoName := "gamersclub_2025_mirage"
+	demoPath := testsutils.GetDemoPath("cs2", demoName)
+	match, err := api.AnalyzeDemo(demoPath, api.AnalyzeDemoOptions{
+		Source: constants.DemoSourceGamersclub,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedPlayerCount := 10
+	expectedMaxRounds := 24
+
+	if len(match.Rounds) == 0 {
+		t.Fatalf("expected rounds to be detected")
+	}
+	if len(match.Players()) != expectedPlayerCount {
+		t.Errorf("expected %d players but got %d", expectedPlayerCount, len(match.Players()))
+	}
+	if match.MaxRounds != expectedMaxRounds {
+		t.Errorf("expected max rounds to be %d but got %d", expectedMaxRounds, match.MaxRounds)
+	}
+	if match.TeamA.Score+match.TeamB.Score != len(match.Rounds) {
+		t.Errorf("expected the scores sum %d to be the round count %d", match.TeamA.Score+match.TeamB.Score, len(match.Rounds))
+	}
+	if match.Winner == nil {
+		t.Errorf("expected a winner")
+	}
+
+	for index, round := range match.Rounds {
+		if round.Number != index+1 {
+			t.Errorf("expected round %d to have the number %d got %d", index+1, index+1, round.Number)
+		}
+		if index > 0 && round.StartTick < match.Rounds[index-1].EndTick {
+			t.Errorf("expected round %d to start after the end of the previous round", round.Number)
+		}
+	}
+
+	// The knife round must not be the first round.
+	firstRoundKillCount := 0
+	firstRoundKnifeKillCount := 0
+	for _, kill := range match.Kills {
+		if kill.RoundNumber != 1 {
+			continue
+		}
+		firstRoundKillCount++
+		if kill.WeaponName == constants.WeaponKnife {
+			firstRoundKnifeKillCount++
+		}
+	}
+	if firstRoundKillCount > 0 && firstRoundKillCount == firstRoundKnifeKillCount {
+		t.Errorf("expected the knife round to be ignored")
+	}
+}
@@ -0,0 +1,114 @@
+package tests
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/akiver/cs-demo-analyzer/pkg/api"
+	"github.com/akiver/cs-demo-analyzer/pkg/api/constants"
+	"github.com/akiver/cs-demo-analyzer/tests/snapshot"
+	"github.com/akiver/cs-demo-analyzer/tests/testsutils"
+)
+
+var updateSnapshots = flag.Bool("update", false, "Write the exports of the demos into the golden snapshots instead of comparing them")
+
+// Same sources as the demos tests, the source of other demos is detected.
+var snapshotDemoSources = map[string]constants.DemoSource{
+	"5eplay_g161_20231231135244670959707_2023_mirage":     constants.DemoSourceFiveEPlay,
+	"5eplay_g161-20240107155319726585099_2023_nuke":       constants.DemoSourceFiveEPlay,
+	"challengermode_6c306e56-8170-4092-b402-08dbf813e452": constants.DemoSourceFaceIt,
+	"ebot_monte_vs_og_roobet_cup_2023_anubis":             constants.DemoSourceEbot,
+	"esplay_nnQccdWWJtkc_2025_vertigo":                    constants.DemoSourceEsplay,
+	"esplay_ntfNCNcmKCQc_2025_mirage":                     constants.DemoSourceEsplay,
+	"esplay_nvBBvqNCfFHV_2025_train":                      constants.DemoSourceEsplay,
+	"esportal_6008132_2023_mirage":                        constants.DemoSourceEbot,
+	"esportal_6045888_2024_mirage":                        constants.DemoSourceEbot,
+	"fastcup_11851975_11876310_202312171749_2023_mirage":  constants.DemoSourceFastcup,
+	"gamersclub_2025_mirage":                              constants.DemoSourceGamersclub,
+	"matchzy_aurora_vs_3dmax_m3_anubis":                   constants.DemoSourceMatchZy,
+	"matchzy_bleed_vs_parivision_2024_mirage":             constants.DemoSourceMatchZy,
+	"matchzy_iskandear_vs_kirill_2024_train":              constants.DemoSourceMatchZy,
+	"matchzy_pressure_vs_cyphin_2024_nuke":                constants.DemoSourceMatchZy,
+	"renown_match_1363_2025_ancient":                      constants.DemoSourceRenown,
+	"renown_match_8_2025_mirage":                          constants.DemoSourceRenown,
+}
+
+// Exports the demo in every format and returns the normalized files by format folder.
+func exportDemoSnapshot(t *testing.T, demoPath string, demoName string) snapshot.Files {
+	demosFolderPath := filepath.Dir(demoPath)
+	files := make(snapshot.Files)
+	for _, format := range constants.ExportFormats {
+		outputFolderPath := t.TempDir()
+		outputPath := outputFolderPath
+		if format == constants.ExportFormatJSON {
+			outputPath = filepath.Join(outputFolderPath, demoName+".json")
+		}
+
+		err := api.AnalyzeAndExportDemo(demoPath, outputPath, api.AnalyzeAndExportDemoOptions{
+			Source: snapshotDemoSources[demoName],
+			Format: format,
+		})
+		if err != nil {
+			t.Fatalf("failed to export demo as %s: %v", format, err)
+		}
+
+		entries, err := os.ReadDir(outputFolderPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, entry := range entries {
+			content, err := os.ReadFile(filepath.Join(outputFolderPath, entry.Name()))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			switch name := strings.TrimPrefix(entry.Name(), demoName+"_"); {
+			case format == constants.ExportFormatJSON:
+				jsonFiles, err := snapshot.NormalizeJSON(content, demosFolderPath)
+				if err != nil {
+					t.Fatalf("failed to normalize %s: %v", entry.Name(), err)
+				}
+				for jsonName, jsonContent := range jsonFiles {
+					files[string(format)+"/"+jsonName] = jsonContent
+				}
+			case strings.HasSuffix(name, ".csv"):
+				csvContent, err := snapshot.NormalizeCSV(content, demosFolderPath)
+				if err != nil {
+					t.Fatalf("failed to normalize %s: %v", entry.Name(), err)
+				}
+				files[string(format)+"/"+name] = csvContent
+			default:
+				files[string(format)+"/"+name] = content
+			}
+		}
+	}
+
+	return files
+}
+
+// Compares the exports of every CS2 demo with the golden files of testdata/snapshots.
+// Demos without snapshot are skipped until they are created with -update.
+func TestExportSnapshots(t *testing.T) {
+	demoPaths, err := filepath.Glob(filepath.Join(testsutils.GetDemosFolderPath("cs2"), "*.dem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(demoPaths) == 0 {
+		t.Skip("no demos found, run ./download-demos.sh")
+	}
+
+	for _, demoPath := range demoPaths {
+		demoName := strings.TrimSuffix(filepath.Base(demoPath), ".dem")
+		t.Run(demoName, func(t *testing.T) {
+			snapshotFolderPath := filepath.Join("testdata", "snapshots", "cs2", demoName)
+			if _, err := os.Stat(snapshotFolderPath); err != nil && !*updateSnapshots {
+				t.Skipf("no snapshot, run go test ./tests -run TestExportSnapshots -update to create it")
+			}
+
+			snapshot.Compare(t, snapshotFolderPath, exportDemoSnapshot(t, demoPath, demoName), *updateSnapshots)
+		})
+	}
+}
@@ -0,0 +1,214 @@
+// Package snapshot stores normalized exports as golden files and compares new exports with them.
+package snapshot
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Files bigger than this are stored as a SHA-256 hash to keep the repository small.
+const HashThreshold = 256 * 1024
+
+// Weapons and grenades unique IDs are random.
+var ulidRegex = regexp.MustCompile(`\b[0-9A-HJKMNP-TV-Z]{26}\b`)
+
+// Normalized files by path relative to the snapshot folder.
+type Files map[string][]byte
+
+// Replaces what depends on the machine or the run: the demos folder path and ULIDs, which are replaced in their
+// order of appearance.
+func normalizeText(content []byte, demosFolderPath string) []byte {
+	for _, path := range []string{demosFolderPath, filepath.ToSlash(demosFolderPath)} {
+		content = bytes.ReplaceAll(content, []byte(path), []byte("{demos}"))
+	}
+
+	ids := make(map[string]string)
+	return ulidRegex.ReplaceAllFunc(content, func(id []byte) []byte {
+		placeholder, exists := ids[string(id)]
+		if !exists {
+			placeholder = fmt.Sprintf("ulid-%d", len(ids)+1)
+			ids[string(id)] = placeholder
+		}
+		return []byte(placeholder)
+	})
+}
+
+// Rows are sorted because some tables are exported from maps, the header is kept first.
+func NormalizeCSV(content []byte, demosFolderPath string) ([]byte, error) {
+	reader := csv.NewReader(bytes.NewReader(normalizeText(content, demosFolderPath)))
+	reader.FieldsPerRecord = -1
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) > 1 {
+		slices.SortFunc(rows[1:], func(a []string, b []string) int {
+			return slices.Compare(a, b)
+		})
+	}
+
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	if err := writer.WriteAll(rows); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// Splits a JSON export into a file per top level field, arrays are sorted like CSV rows.
+func NormalizeJSON(content []byte, demosFolderPath string) (Files, error) {
+	decoder := json.NewDecoder(bytes.NewReader(normalizeText(content, demosFolderPath)))
+	decoder.UseNumber()
+	var match map[string]any
+	if err := decoder.Decode(&match); err != nil {
+		return nil, err
+	}
+
+	files := make(Files)
+	for name, value := range match {
+		if items, ok := value.([]any); ok {
+			encodedItems := make([]json.RawMessage, len(items))
+			for index, item := range items {
+				encodedItem, err := json.Marshal(item)
+				if err != nil {
+					return nil, err
+				}
+				encodedItems[index] = encodedItem
+			}
+			slices.SortFunc(encodedItems, func(a json.RawMessage, b json.RawMessage) int {
+				return bytes.Compare(a, b)
+			})
+			value = encodedItems
+		}
+
+		data, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		files[name+".json"] = append(data, '\n')
+	}
+
+	return files, nil
+}
+
+func hashContent(content []byte) []byte {
+	hash := sha256.Sum256(content)
+	return fmt.Appendf(nil, "sha256 %s\nsize %d\nlines %d\n", hex.EncodeToString(hash[:]), len(content), bytes.Count(content, []byte("\n")))
+}
+
+// Big files are replaced by their hash, their name ends with .sha256.
+func (files Files) withHashes() Files {
+	hashedFiles := make(Files)
+	for name, content := range files {
+		if len(content) > HashThreshold {
+			hashedFiles[name+".sha256"] = hashContent(content)
+		} else {
+			hashedFiles[name] = content
+		}
+	}
+
+	return hashedFiles
+}
+
+func readFolder(folderPath string) (Files, error) {
+	files := make(Files)
+	err := filepath.WalkDir(folderPath, func(path string, entry os.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(folderPath, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(name)] = content
+
+		return nil
+	})
+
+	return files, err
+}
+
+func firstDifferentLine(expected []byte, actual []byte) string {
+	expectedLines := strings.Split(string(expected), "\n")
+	actualLines := strings.Split(string(actual), "\n")
+	for index := range max(len(expectedLines), len(actualLines)) {
+		expectedLine, actualLine := "", ""
+		if index < len(expectedLines) {
+			expectedLine = expectedLines[index]
+		}
+		if index < len(actualLines) {
+			actualLine = actualLines[index]
+		}
+		if expectedLine != actualLine {
+			return fmt.Sprintf("line %d\n  expected: %s\n  actual:   %s", index+1, expectedLine, actualLine)
+		}
+	}
+
+	return ""
+}
+
+// Compares files with the golden files of folderPath, the folder is replaced with files when update is true.
+func Compare(t *testing.T, folderPath string, files Files, update bool) {
+	t.Helper()
+	files = files.withHashes()
+
+	if update {
+		if err := os.RemoveAll(folderPath); err != nil {
+			t.Fatal(err)
+		}
+		for name, content := range files {
+			path := filepath.Join(folderPath, filepath.FromSlash(name))
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, content, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return
+	}
+
+	goldenFiles, err := readFolder(folderPath)
+	if err != nil {
+		t.Fatalf("failed to read snapshot %s: %v", folderPath, err)
+	}
+
+	names := []string{}
+	for name := range files {
+		names = append(names, name)
+	}
+	for name := range goldenFiles {
+		if _, exists := files[name]; !exists {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		content, exists := files[name]
+		goldenContent, goldenExists := goldenFiles[name]
+		switch {
+		case !goldenExists:
+			t.Errorf("%s: new file, run the tests with -update if it's expected", name)
+		case !exists:
+			t.Errorf("%s: file not exported anymore, run the tests with -update if it's expected", name)
+		case !bytes.Equal(content, goldenContent):
+			t.Errorf("%s: snapshot mismatch at %s", name, firstDifferentLine(goldenContent, content))
+		}
+	}
+}
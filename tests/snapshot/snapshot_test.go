@@ -0,0 +1,72 @@
+package snapshot
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeCSV(t *testing.T) {
+	demosFolderPath := filepath.Join("home", "user", "cs-demos", "cs2")
+	content := "name,path,id\n" +
+		"b," + filepath.Join(demosFolderPath, "demo.dem") + ",01HZX3Q6V1J8K4Y9W2N7R5T0AB\n" +
+		"a,path,01HZX3Q6V1J8K4Y9W2N7R5T0CD\n" +
+		"c,path,01HZX3Q6V1J8K4Y9W2N7R5T0AB\n"
+
+	normalized, err := NormalizeCSV([]byte(content), demosFolderPath)
+	if err != nil {
+		t.Fatalf("expected no error got %v", err)
+	}
+
+	expected := "name,path,id\n" +
+		"a,path,ulid-2\n" +
+		"b," + filepath.Join("{demos}", "demo.dem") + ",ulid-1\n" +
+		"c,path,ulid-1\n"
+	if string(normalized) != expected {
+		t.Errorf("expected %q got %q", expected, normalized)
+	}
+}
+
+func TestNormalizeJSON(t *testing.T) {
+	content := `{"checksum":"abc","kills":[{"tick":20},{"tick":10}],"teamA":{"name":"A"}}`
+
+	files, err := NormalizeJSON([]byte(content), "demos")
+	if err != nil {
+		t.Fatalf("expected no error got %v", err)
+	}
+
+	expectedFiles := map[string]string{
+		"checksum.json": "\"abc\"\n",
+		"kills.json":    "[\n  {\n    \"tick\": 10\n  },\n  {\n    \"tick\": 20\n  }\n]\n",
+		"teamA.json":    "{\n  \"name\": \"A\"\n}\n",
+	}
+	if len(files) != len(expectedFiles) {
+		t.Fatalf("expected %d files got %d", len(expectedFiles), len(files))
+	}
+	for name, expected := range expectedFiles {
+		if string(files[name]) != expected {
+			t.Errorf("expected %s to be %q got %q", name, expected, files[name])
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	folderPath := t.TempDir()
+	files := Files{
+		"csv/kills.csv":     []byte("tick\n10\n"),
+		"csv/positions.csv": bytes.Repeat([]byte("1,2,3\n"), HashThreshold),
+	}
+
+	Compare(t, folderPath, files, true)
+	goldenFiles, err := readFolder(folderPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, exists := goldenFiles["csv/positions.csv.sha256"]
+	if !exists || !strings.HasPrefix(string(hash), "sha256 ") {
+		t.Errorf("expected the big file to be hashed got %v", goldenFiles)
+	}
+
+	Compare(t, folderPath, files, false)
+}
@@ -15,3 +15,8 @@ func GetDemoPath(gameFolder string, name string) string {
 	repositoryRoot := filepath.Clean(filepath.Join(filepath.Dir(currentFilePath), "..", ".."))
 	return filepath.Join(repositoryRoot, "cs-demos", gameFolder, name+".dem")
 }
+
+// GetDemosFolderPath returns the path to the folder containing the demos of the given game.
+func GetDemosFolderPath(gameFolder string) string {
+	return filepath.Dir(GetDemoPath(gameFolder, "demo"))
+}